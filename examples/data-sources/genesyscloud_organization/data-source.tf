@@ -0,0 +1,2 @@
+data "genesyscloud_organization" "current" {
+}
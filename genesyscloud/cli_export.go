@@ -0,0 +1,211 @@
+package genesyscloud
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Short flag names for common export attributes. Every attribute can also be set with its full name.
+var exportCommandFlagAliases = map[string]string{
+	"dir":   "directory",
+	"types": "resource_types",
+}
+
+// stringListFlag collects the values of a flag that may be repeated
+type stringListFlag []interface{}
+
+func (f *stringListFlag) String() string {
+	values := make([]string, len(*f))
+	for i, val := range *f {
+		values[i] = val.(string)
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *stringListFlag) Set(val string) error {
+	*f = append(*f, val)
+	return nil
+}
+
+// objectListFlag collects the JSON object values of a flag that may be repeated, for attributes with nested blocks
+type objectListFlag []interface{}
+
+func (f *objectListFlag) String() string {
+	values := make([]string, len(*f))
+	for i, val := range *f {
+		encoded, _ := json.Marshal(val)
+		values[i] = string(encoded)
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *objectListFlag) Set(val string) error {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(val), &object); err != nil {
+		return fmt.Errorf("value must be a JSON object: %v", err)
+	}
+	*f = append(*f, object)
+	return nil
+}
+
+// RunExportCommand runs the same export as the genesyscloud_tf_export resource without Terraform.
+// The provider is configured from the usual environment variables, e.g. GENESYSCLOUD_OAUTHCLIENT_ID, and each
+// attribute of the export resource can be set with a flag of the same name, e.g. --include_state_file.
+func RunExportCommand(version string, args []string, output io.Writer) error {
+	exportResource := resourceTfExport()
+	rawConfig, err := parseExportCommandArgs(exportResource.Schema, args, output)
+	if err == flag.ErrHelp {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	provider := New(version)()
+	providerConfig := terraform.NewResourceConfigRaw(map[string]interface{}{})
+	if diagErr := provider.Validate(providerConfig); diagErr.HasError() {
+		return diagnosticsError(diagErr, output)
+	}
+	if diagErr := provider.Configure(ctx, providerConfig); diagErr.HasError() {
+		return diagnosticsError(diagErr, output)
+	}
+
+	resourceConfig := terraform.NewResourceConfigRaw(rawConfig)
+	if diagErr := exportResource.Validate(resourceConfig); diagErr.HasError() {
+		return diagnosticsError(diagErr, output)
+	}
+	instanceDiff, err := exportResource.Diff(ctx, nil, resourceConfig, provider.Meta())
+	if err != nil {
+		return err
+	}
+	state, diagErr := exportResource.Apply(ctx, nil, instanceDiff, provider.Meta())
+	if err := diagnosticsError(diagErr, output); err != nil {
+		return err
+	}
+	if state == nil || state.ID == "" {
+		return fmt.Errorf("export did not complete")
+	}
+
+	fmt.Fprintf(output, "Exported %s\n", state.ID)
+	return nil
+}
+
+// Parses the command line flags into the raw config of an export resource
+func parseExportCommandArgs(schemaMap map[string]*schema.Schema, args []string, output io.Writer) (map[string]interface{}, error) {
+	flagSet := flag.NewFlagSet("export", flag.ContinueOnError)
+	flagSet.SetOutput(output)
+	config, err := addExportFlags(flagSet, schemaMap)
+	if err != nil {
+		return nil, err
+	}
+	flagSet.Usage = func() {
+		fmt.Fprintf(output, "Usage: terraform-provider-genesyscloud export [flags]\n\n")
+		fmt.Fprintf(output, "Exports Genesys Cloud resources to Terraform config files. The provider is configured from the GENESYSCLOUD_* environment variables. List flags may be repeated.\n\n")
+		flagSet.PrintDefaults()
+	}
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	if flagSet.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flagSet.Args(), " "))
+	}
+
+	// Only attributes set on the command line are part of the config, so schema defaults apply to the rest
+	rawConfig := make(map[string]interface{})
+	flagSet.Visit(func(f *flag.Flag) {
+		attr := f.Name
+		if alias, ok := exportCommandFlagAliases[attr]; ok {
+			attr = alias
+		}
+		rawConfig[attr] = config[attr]()
+	})
+	return rawConfig, nil
+}
+
+// Defines a flag for each attribute of the export resource and returns a map of attributes to their flag values
+func addExportFlags(flagSet *flag.FlagSet, schemaMap map[string]*schema.Schema) (map[string]func() interface{}, error) {
+	values := make(map[string]func() interface{}, len(schemaMap))
+	for _, attr := range sortedSchemaKeys(schemaMap) {
+		attrSchema := schemaMap[attr]
+		usage := attrSchema.Description
+		if attrSchema.Default != nil {
+			usage += fmt.Sprintf(" Defaults to %v.", attrSchema.Default)
+		}
+		usage = strings.TrimSpace(usage)
+
+		switch attrSchema.Type {
+		case schema.TypeString:
+			val := flagSet.String(attr, "", usage)
+			values[attr] = func() interface{} { return *val }
+		case schema.TypeBool:
+			val := flagSet.Bool(attr, false, usage)
+			values[attr] = func() interface{} { return *val }
+		case schema.TypeInt:
+			val := flagSet.Int(attr, 0, usage)
+			values[attr] = func() interface{} { return *val }
+		case schema.TypeList, schema.TypeSet:
+			switch elem := attrSchema.Elem.(type) {
+			case *schema.Resource:
+				val := &objectListFlag{}
+				flagSet.Var(val, attr, usage+" Each value is a JSON object with the attributes "+strings.Join(sortedSchemaKeys(elem.Schema), ", ")+".")
+				values[attr] = func() interface{} { return []interface{}(*val) }
+			case *schema.Schema:
+				if elem.Type != schema.TypeString {
+					return nil, fmt.Errorf("export attribute %s cannot be set with a flag", attr)
+				}
+				val := &stringListFlag{}
+				flagSet.Var(val, attr, usage)
+				values[attr] = func() interface{} { return []interface{}(*val) }
+			default:
+				return nil, fmt.Errorf("export attribute %s cannot be set with a flag", attr)
+			}
+		default:
+			return nil, fmt.Errorf("export attribute %s cannot be set with a flag", attr)
+		}
+	}
+
+	for alias, attr := range exportCommandFlagAliases {
+		if f := flagSet.Lookup(attr); f != nil {
+			flagSet.Var(f.Value, alias, fmt.Sprintf("Alias for --%s.", attr))
+		}
+	}
+	return values, nil
+}
+
+func sortedSchemaKeys(schemaMap map[string]*schema.Schema) []string {
+	keys := make([]string, 0, len(schemaMap))
+	for key := range schemaMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Writes warnings to the output and returns an error with the summary of each error diagnostic
+func diagnosticsError(diags diag.Diagnostics, output io.Writer) error {
+	var errors diag.Diagnostics
+	for _, d := range diags {
+		if d.Severity == diag.Warning {
+			fmt.Fprintf(output, "Warning: %s\n", d.Summary)
+			if d.Detail != "" {
+				fmt.Fprintf(output, "%s\n", d.Detail)
+			}
+			continue
+		}
+		errors = append(errors, d)
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", diagnosticsString(errors))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package genesyscloud
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestExportCommandArgs(t *testing.T) {
+	exportResource := resourceTfExport()
+	rawConfig, err := parseExportCommandArgs(exportResource.Schema, []string{
+		"--dir", "./export",
+		"--types", "genesyscloud_user",
+		"--types", "genesyscloud_group",
+		"--include_state_file",
+		"--layout", layoutFilePerType,
+		"--replace_attributes", `{"attribute": "genesyscloud_user.email", "pattern": "@example\\.com$", "replacement": "@sandbox.example.com"}`,
+	}, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedConfig := map[string]interface{}{
+		"directory":          "./export",
+		"resource_types":     []interface{}{"genesyscloud_user", "genesyscloud_group"},
+		"include_state_file": true,
+		"layout":             layoutFilePerType,
+		"replace_attributes": []interface{}{
+			map[string]interface{}{"attribute": "genesyscloud_user.email", "pattern": "@example\\.com$", "replacement": "@sandbox.example.com"},
+		},
+	}
+	if !reflect.DeepEqual(rawConfig, expectedConfig) {
+		t.Errorf("Unexpected config %v. Expected %v", rawConfig, expectedConfig)
+	}
+	if diagErr := exportResource.Validate(terraform.NewResourceConfigRaw(rawConfig)); diagErr.HasError() {
+		t.Errorf("Expected config to be valid: %v", diagErr)
+	}
+
+	// Schema validation applies to the flags
+	rawConfig, err = parseExportCommandArgs(exportResource.Schema, []string{"--include_state_file", "--import_mode", importModeBlocks}, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diagErr := exportResource.Validate(terraform.NewResourceConfigRaw(rawConfig)); !diagErr.HasError() {
+		t.Error("Expected conflicting flags to be invalid")
+	}
+
+	if _, err := parseExportCommandArgs(exportResource.Schema, []string{"--unknown"}, ioutil.Discard); err == nil {
+		t.Error("Expected an error for an unknown flag")
+	}
+}
@@ -25,6 +25,16 @@ func dataSourceFlow() *schema.Resource {
 	}
 }
 
+func getFlowName(_ context.Context, id string, sdkConfig *platformclientv2.Configuration) (string, diag.Diagnostics) {
+	archAPI := platformclientv2.NewArchitectApiWithConfig(sdkConfig)
+
+	flow, _, getErr := archAPI.GetFlow(id, false)
+	if getErr != nil {
+		return "", diag.Errorf("Failed to get flow %s: %s", id, getErr)
+	}
+	return *flow.Name, nil
+}
+
 func dataSourceFlowRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	sdkConfig := m.(*providerMeta).ClientConfig
 	archAPI := platformclientv2.NewArchitectApiWithConfig(sdkConfig)
@@ -0,0 +1,90 @@
+package genesyscloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
+)
+
+func dataSourceOrganization() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for the Genesys Cloud organization of the provider's credentials.",
+		ReadContext: readWithPooledClient(dataSourceOrganizationRead),
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Organization name.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"domain": {
+				Description: "Organization domain.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"default_country_code": {
+				Description: "Default country code of the organization, e.g. 'US'.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"default_language": {
+				Description: "Default language of the organization, e.g. 'en-us'.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"home_division_id": {
+				Description: "ID of the home division of the organization.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrganizationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	org, diagErr := getOrganization(m.(*providerMeta).ClientConfig)
+	if diagErr != nil {
+		return diagErr
+	}
+	homeDivisionID, diagErr := getHomeDivisionID(m)
+	if diagErr != nil {
+		return diagErr
+	}
+
+	d.SetId(*org.Id)
+	d.Set("name", *org.Name)
+	d.Set("home_division_id", homeDivisionID)
+
+	if org.Domain != nil {
+		d.Set("domain", *org.Domain)
+	} else {
+		d.Set("domain", nil)
+	}
+
+	if org.DefaultCountryCode != nil {
+		d.Set("default_country_code", *org.DefaultCountryCode)
+	} else {
+		d.Set("default_country_code", nil)
+	}
+
+	if org.DefaultLanguage != nil {
+		d.Set("default_language", *org.DefaultLanguage)
+	} else {
+		d.Set("default_language", nil)
+	}
+	return nil
+}
+
+// Returns the organization of the credentials used by an SDK client. The organization always has an ID and name.
+func getOrganization(sdkConfig *platformclientv2.Configuration) (*platformclientv2.Organization, diag.Diagnostics) {
+	orgAPI := platformclientv2.NewOrganizationApiWithConfig(sdkConfig)
+	org, _, err := orgAPI.GetOrganizationsMe()
+	if err != nil {
+		return nil, diag.Errorf("Failed to query the organization: %s", err)
+	}
+	if org == nil || org.Id == nil || org.Name == nil {
+		return nil, diag.Errorf("Failed to query the organization: the response has no ID or name")
+	}
+	return org, nil
+}
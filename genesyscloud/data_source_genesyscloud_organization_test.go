@@ -0,0 +1,28 @@
+package genesyscloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceOrganization(t *testing.T) {
+	orgDataSource := "org-data"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "genesyscloud_organization" "` + orgDataSource + `" {
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.genesyscloud_organization."+orgDataSource, "id"),
+					resource.TestCheckResourceAttrSet("data.genesyscloud_organization."+orgDataSource, "name"),
+					resource.TestCheckResourceAttrSet("data.genesyscloud_organization."+orgDataSource, "home_division_id"),
+				),
+			},
+		},
+	})
+}
@@ -25,6 +25,16 @@ func dataSourceScript() *schema.Resource {
 	}
 }
 
+func getScriptName(_ context.Context, id string, sdkConfig *platformclientv2.Configuration) (string, diag.Diagnostics) {
+	scriptsAPI := platformclientv2.NewScriptsApiWithConfig(sdkConfig)
+
+	script, _, getErr := scriptsAPI.GetScript(id)
+	if getErr != nil {
+		return "", diag.Errorf("Failed to get script %s: %s", id, getErr)
+	}
+	return *script.Name, nil
+}
+
 func dataSourceScriptRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	sdkConfig := m.(*providerMeta).ClientConfig
 	scriptsAPI := platformclientv2.NewScriptsApiWithConfig(sdkConfig)
@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -36,24 +37,51 @@ func New(version string) func() *schema.Provider {
 			Schema: map[string]*schema.Schema{
 				"oauthclient_id": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_OAUTHCLIENT_ID", nil),
 					Description: "OAuthClient ID found on the OAuth page of Admin UI. Can be set with the `GENESYSCLOUD_OAUTHCLIENT_ID` environment variable.",
 				},
 				"oauthclient_secret": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_OAUTHCLIENT_SECRET", nil),
 					Description: "OAuthClient secret found on the OAuth page of Admin UI. Can be set with the `GENESYSCLOUD_OAUTHCLIENT_SECRET` environment variable.",
 					Sensitive:   true,
 				},
+				"oauthclient_credentials_command": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_OAUTHCLIENT_CREDENTIALS_COMMAND", nil),
+					Description: "Command that prints the OAuthClient ID and secret as a JSON object, e.g. `{\"client_id\": \"...\", \"client_secret\": \"...\"}`, to stdout. The command is run with the system shell when the provider is configured, and is used instead of `oauthclient_id` and `oauthclient_secret`. Can be set with the `GENESYSCLOUD_OAUTHCLIENT_CREDENTIALS_COMMAND` environment variable.",
+				},
+				"access_token": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_ACCESS_TOKEN", nil),
+					Description: "OAuth access token used for all requests instead of authorizing an OAuthClient. The token is not refreshed, so it must be valid for the whole run. Takes precedence over the OAuthClient attributes. Can be set with the `GENESYSCLOUD_ACCESS_TOKEN` environment variable.",
+					Sensitive:   true,
+				},
 				"aws_region": {
 					Type:         schema.TypeString,
-					Required:     true,
+					Optional:     true,
 					DefaultFunc:  schema.EnvDefaultFunc("GENESYSCLOUD_REGION", nil),
-					Description:  "AWS region where org exists. e.g. us-east-1. Can be set with the `GENESYSCLOUD_REGION` environment variable.",
+					Description:  "AWS region where org exists. e.g. us-east-1. Required unless `sdk_base_path` is set. Can be set with the `GENESYSCLOUD_REGION` environment variable.",
 					ValidateFunc: validation.StringInSlice(getAllowedRegions(), true),
 				},
+				"sdk_base_path": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("GENESYSCLOUD_SDK_BASE_PATH", nil),
+					Description:  "Base URL of the Genesys Cloud API, e.g. `https://api.mypurecloud.com`. Overrides the URL derived from `aws_region`, e.g. for private endpoints or regions not yet known to the provider. Can be set with the `GENESYSCLOUD_SDK_BASE_PATH` environment variable.",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+				"auth_base_path": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("GENESYSCLOUD_AUTH_BASE_PATH", nil),
+					Description:  "Base URL of the Genesys Cloud login service used to authorize the OAuthClient, e.g. `https://login.mypurecloud.com`. Defaults to the API base URL with `api.` replaced by `login.`. Can be set with the `GENESYSCLOUD_AUTH_BASE_PATH` environment variable.",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
 				"sdk_debug": {
 					Type:        schema.TypeBool,
 					Optional:    true,
@@ -64,9 +92,70 @@ func New(version string) func() *schema.Provider {
 					Type:         schema.TypeInt,
 					Optional:     true,
 					DefaultFunc:  schema.EnvDefaultFunc("GENESYSCLOUD_TOKEN_POOL_SIZE", 10),
-					Description:  "Max number of OAuth tokens in the token pool. Can be set with the `GENESYSCLOUD_TOKEN_POOL_SIZE` environment variable.",
+					Description:  "Max number of OAuth tokens in the token pool. Tokens are requested when first needed and renewed when they expire. Can be set with the `GENESYSCLOUD_TOKEN_POOL_SIZE` environment variable.",
 					ValidateFunc: validation.IntBetween(1, 20),
 				},
+				"expected_org_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_EXPECTED_ORG_ID", nil),
+					Description: "ID of the org the credentials must belong to. The provider fails to configure for any other org, e.g. to prevent dev config from being applied with prod credentials. Can be set with the `GENESYSCLOUD_EXPECTED_ORG_ID` environment variable.",
+				},
+				"expected_org_name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_EXPECTED_ORG_NAME", nil),
+					Description: "Name of the org the credentials must belong to. The provider fails to configure for any other org. Can be set with the `GENESYSCLOUD_EXPECTED_ORG_NAME` environment variable.",
+				},
+				"read_only": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("GENESYSCLOUD_READ_ONLY", false),
+					Description: "Refuses to create, update or delete resources, so the provider can be used for plans, data sources and exports without changing the org. Can be set with the `GENESYSCLOUD_READ_ONLY` environment variable.",
+				},
+				"retry": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Retry and timeout policy for requests to Genesys Cloud.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"max_attempts": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Description:  "Max number of attempts of a request that is rate limited or fails with a server error. Also limits the attempts of updates that conflict with a concurrent change to the same object. Defaults to 21 attempts of rate limited requests and server errors, and 10 attempts of conflicting updates.",
+								ValidateFunc: validation.IntBetween(1, 100),
+							},
+							"min_backoff_ms": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Default:      int(defaultRetryPolicy.MinBackoff.Milliseconds()),
+								Description:  "Min wait in milliseconds before a request is retried. Waits double with each retry of a rate limited request or server error, unless the response has a `Retry-After` header.",
+								ValidateFunc: validation.IntAtLeast(0),
+							},
+							"max_backoff_ms": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Default:      int(defaultRetryPolicy.MaxBackoff.Milliseconds()),
+								Description:  "Max wait in milliseconds before a request is retried.",
+								ValidateFunc: validation.IntAtLeast(0),
+							},
+							"jitter_ms": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Default:      int(defaultRetryPolicy.Jitter.Milliseconds()),
+								Description:  "Max random time in milliseconds added to each wait, so requests that failed together are not retried together.",
+								ValidateFunc: validation.IntAtLeast(0),
+							},
+							"eventual_consistency_timeout_seconds": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								Description:  "Max time in seconds to wait for a change to become visible to later requests, e.g. for a new object to be found by name. Defaults to the timeout of each resource and data source, between 5 and 30 seconds.",
+								ValidateFunc: validation.IntAtLeast(1),
+							},
+						},
+					},
+				},
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"genesyscloud_architect_datatable":                         resourceArchitectDatatable(),
@@ -123,6 +212,7 @@ func New(version string) func() *schema.Provider {
 				"genesyscloud_integration_credential":                      dataSourceIntegrationCredential(),
 				"genesyscloud_location":                                    dataSourceLocation(),
 				"genesyscloud_oauth_client":                                dataSourceOAuthClient(),
+				"genesyscloud_organization":                                dataSourceOrganization(),
 				"genesyscloud_routing_language":                            dataSourceRoutingLanguage(),
 				"genesyscloud_routing_queue":                               dataSourceRoutingQueue(),
 				"genesyscloud_routing_skill":                               dataSourceRoutingSkill(),
@@ -147,26 +237,90 @@ func New(version string) func() *schema.Provider {
 }
 
 type providerMeta struct {
-	Version      string
+	Version string
+	// Client acquired from the pool for the current operation. Only set on the copies of the meta made for pooled clients.
 	ClientConfig *platformclientv2.Configuration
 	Domain       string
+	ReadOnly     bool
+
+	// State of each configured provider. Copies of the meta made for pooled clients share these.
+	ClientPool     *SDKClientPool
+	HomeDivision   *homeDivisionCache
+	DatatableCache *sync.Map
+	RetryPolicy    *retryPolicy
 }
 
 func configure(version string) schema.ConfigureContextFunc {
 	return func(context context.Context, data *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		basePath, err := getProviderBasePath(data)
+		if err != nil {
+			return nil, err
+		}
+
+		retryPolicy, err := getRetryPolicy(data)
+		if err != nil {
+			return nil, err
+		}
+
+		// Credentials are resolved once so a credentials command is not run for each client
+		credentials, err := getProviderCredentials(data)
+		if err != nil {
+			return nil, err
+		}
+
 		// Initialize the SDK Client pool
-		err := InitSDKClientPool(data.Get("token_pool_size").(int), version, data)
+		clientPool, err := InitSDKClientPool(data.Get("token_pool_size").(int), version, data, credentials)
+		if err != nil {
+			return nil, err
+		}
+		clientPool.logStatsOnStop(context)
+
+		// Clients are authorized when they are first acquired from the pool, so a provider that makes no requests
+		// does not need valid credentials
+		err = verifyExpectedOrg(data, clientPool)
 		if err != nil {
 			return nil, err
 		}
 		return &providerMeta{
-			Version:      version,
-			ClientConfig: platformclientv2.GetDefaultConfiguration(),
-			Domain:       getRegionDomain(data.Get("aws_region").(string)),
+			Version:        version,
+			Domain:         getBasePathDomain(basePath),
+			ReadOnly:       data.Get("read_only").(bool),
+			ClientPool:     clientPool,
+			HomeDivision:   &homeDivisionCache{},
+			DatatableCache: &sync.Map{},
+			RetryPolicy:    retryPolicy,
 		}, nil
 	}
 }
 
+// Returns an error if the credentials do not belong to the org set by expected_org_id or expected_org_name
+func verifyExpectedOrg(data *schema.ResourceData, clientPool *SDKClientPool) diag.Diagnostics {
+	expectedID := data.Get("expected_org_id").(string)
+	expectedName := data.Get("expected_org_name").(string)
+	if expectedID == "" && expectedName == "" {
+		return nil
+	}
+
+	clientConfig, err := clientPool.acquire()
+	if err != nil {
+		return err
+	}
+	defer clientPool.release(clientConfig)
+
+	org, err := getOrganization(clientConfig)
+	if err != nil {
+		return err
+	}
+	if expectedID != "" && *org.Id != expectedID {
+		return diag.Errorf("The credentials belong to org %s (%s), not expected_org_id %s. Check the credentials in the environment.", *org.Name, *org.Id, expectedID)
+	}
+	if expectedName != "" && *org.Name != expectedName {
+		return diag.Errorf("The credentials belong to org %s (%s), not expected_org_name %s. Check the credentials in the environment.", *org.Name, *org.Id, expectedName)
+	}
+	log.Printf("Verified the credentials belong to org %s (%s)", *org.Name, *org.Id)
+	return nil
+}
+
 func getRegionMap() map[string]string {
 	return map[string]string{
 		"dca":            "inindca.com",
@@ -201,10 +355,33 @@ func getRegionBasePath(region string) string {
 	return "https://api." + getRegionDomain(region)
 }
 
+// Returns the base path of the API from sdk_base_path, or from the region if it is not set
+func getProviderBasePath(data *schema.ResourceData) (string, diag.Diagnostics) {
+	if basePath := data.Get("sdk_base_path").(string); basePath != "" {
+		return strings.TrimSuffix(basePath, "/"), nil
+	}
+	region := data.Get("aws_region").(string)
+	if region == "" {
+		return "", diag.Errorf("Either aws_region or sdk_base_path must be set.")
+	}
+	return getRegionBasePath(region), nil
+}
+
+// Returns the domain of an API base path, e.g. mypurecloud.com for https://api.mypurecloud.com
+func getBasePathDomain(basePath string) string {
+	host := basePath
+	if basePathURL, err := url.Parse(basePath); err == nil && basePathURL.Hostname() != "" {
+		host = basePathURL.Hostname()
+	}
+	return strings.TrimPrefix(host, "api.")
+}
+
+// Initializes an SDK client with the provider config. The client must be authorized before it is used.
 func initClientConfig(data *schema.ResourceData, version string, config *platformclientv2.Configuration) diag.Diagnostics {
-	oauthclientID := data.Get("oauthclient_id").(string)
-	oauthclientSecret := data.Get("oauthclient_secret").(string)
-	basePath := getRegionBasePath(data.Get("aws_region").(string))
+	basePath, diagErr := getProviderBasePath(data)
+	if diagErr != nil {
+		return diagErr
+	}
 
 	config.BasePath = basePath
 	if data.Get("sdk_debug").(bool) {
@@ -218,21 +395,17 @@ func initClientConfig(data *schema.ResourceData, version string, config *platfor
 		config.LoggingConfiguration.SetLogFilePath("sdk_debug.log")
 	}
 	config.AddDefaultHeader("User-Agent", "GC Terraform Provider/"+version)
-	config.RetryConfiguration = &platformclientv2.RetryConfiguration{
-		RetryWaitMin: time.Second * 1,
-		RetryWaitMax: time.Second * 30,
-		RetryMax:     20,
-		RequestLogHook: func(request *http.Request, count int) {
-			if count > 0 && request != nil {
-				log.Printf("Retry #%d for %s %s%s", count, request.Method, request.Host, request.RequestURI)
-			}
-		},
-	}
-
-	err := config.AuthorizeClientCredentials(oauthclientID, oauthclientSecret)
-	if err != nil {
-		return diag.Errorf("Failed to authorize Genesys Cloud client credentials: %v", err)
+
+	retryPolicy, diagErr := getRetryPolicy(data)
+	if diagErr != nil {
+		return diagErr
 	}
+	config.RetryConfiguration = retryPolicy.sdkRetryConfiguration(func(request *http.Request, count int) {
+		if count > 0 && request != nil {
+			log.Printf("Retry #%d for %s %s%s", count, request.Method, request.Host, request.RequestURI)
+		}
+	})
+
 	log.Printf("Initialized Go SDK Client. Debug=%t", data.Get("sdk_debug").(bool))
 	return nil
 }
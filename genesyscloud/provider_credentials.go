@@ -0,0 +1,161 @@
+package genesyscloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
+)
+
+// Max time to wait for the credentials command to print the credentials
+const credentialsCommandTimeout = time.Minute
+
+// Matches the API host of a base path, which is replaced with the login host
+var authHostExpression = regexp.MustCompile(`(?i)//api\.`)
+
+// providerCredentials are used to authorize each SDK client of the provider. Either AccessToken or
+// the OAuthClient ID and secret are set.
+type providerCredentials struct {
+	AccessToken  string
+	ClientID     string
+	ClientSecret string
+
+	// Base path of the login service. If not set, it is derived from the API base path.
+	AuthBasePath string
+}
+
+// Output of the credentials command
+type credentialsCommandOutput struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// Returns the credentials from the provider config. An access token takes precedence over a credentials command,
+// which takes precedence over the OAuthClient ID and secret.
+func getProviderCredentials(data *schema.ResourceData) (*providerCredentials, diag.Diagnostics) {
+	authBasePath := strings.TrimSuffix(data.Get("auth_base_path").(string), "/")
+	if accessToken := data.Get("access_token").(string); accessToken != "" {
+		return &providerCredentials{AccessToken: accessToken}, nil
+	}
+
+	if command := data.Get("oauthclient_credentials_command").(string); command != "" {
+		clientID, clientSecret, err := runCredentialsCommand(command)
+		if err != nil {
+			return nil, diag.Errorf("Failed to get OAuthClient credentials from oauthclient_credentials_command: %v", err)
+		}
+		return &providerCredentials{ClientID: clientID, ClientSecret: clientSecret, AuthBasePath: authBasePath}, nil
+	}
+
+	clientID := data.Get("oauthclient_id").(string)
+	clientSecret := data.Get("oauthclient_secret").(string)
+	if clientID == "" || clientSecret == "" {
+		return nil, diag.Errorf("Genesys Cloud credentials are not configured. Set oauthclient_id and oauthclient_secret, oauthclient_credentials_command, or access_token.")
+	}
+	return &providerCredentials{ClientID: clientID, ClientSecret: clientSecret, AuthBasePath: authBasePath}, nil
+}
+
+// Sets the access token of an SDK client, authorizing the OAuthClient if no access token was provided.
+// Returns the time the token expires, which is zero if it is not known.
+func (c *providerCredentials) authorize(config *platformclientv2.Configuration) (time.Time, diag.Diagnostics) {
+	if c.AccessToken != "" {
+		config.AccessToken = c.AccessToken
+		return time.Time{}, nil
+	}
+
+	authBasePath := c.AuthBasePath
+	if authBasePath == "" {
+		authBasePath = getAuthBasePath(config.BasePath)
+	}
+	expiresIn, err := authorizeClientCredentials(config, authBasePath, c.ClientID, c.ClientSecret)
+	if err != nil {
+		return time.Time{}, diag.Errorf("Failed to authorize Genesys Cloud client credentials with %s: %v", authBasePath, err)
+	}
+	if expiresIn <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(expiresIn), nil
+}
+
+// Returns the base path of the login service for an API base path, e.g. https://login.mypurecloud.com for https://api.mypurecloud.com
+func getAuthBasePath(basePath string) string {
+	return authHostExpression.ReplaceAllString(basePath, "//login.")
+}
+
+// Same as Configuration.AuthorizeClientCredentials, but with a login service that may not be derived from the API
+// base path. Returns the lifetime of the token.
+func authorizeClientCredentials(config *platformclientv2.Configuration, authBasePath string, clientID string, clientSecret string) (time.Duration, error) {
+	headerParams := map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)),
+	}
+	formParams := url.Values{"grant_type": []string{"client_credentials"}}
+	response, err := config.APIClient.CallAPI(authBasePath+"/oauth/token", "POST", nil, headerParams, nil, formParams, "", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var authErrorResponse platformclientv2.AuthErrorResponse
+		if err := json.Unmarshal(response.RawBody, &authErrorResponse); err != nil {
+			return 0, fmt.Errorf("unexpected status %d", response.StatusCode)
+		}
+		return 0, fmt.Errorf("Auth Error: %v (%v - %v)", authErrorResponse.Description, authErrorResponse.Error, authErrorResponse.ErrorDescription)
+	}
+
+	var authResponse platformclientv2.AuthResponse
+	if err := json.Unmarshal(response.RawBody, &authResponse); err != nil {
+		return 0, err
+	}
+	if authResponse.AccessToken == "" {
+		return 0, fmt.Errorf("Auth Error: No access token found")
+	}
+	config.AccessToken = authResponse.AccessToken
+	return time.Duration(authResponse.ExpiresIn) * time.Second, nil
+}
+
+// Runs the credentials command with the system shell and parses the client ID and secret from its output
+func runCredentialsCommand(command string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialsCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", "", fmt.Errorf("command did not complete within %v", credentialsCommandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", "", fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", "", err
+	}
+
+	// The output is not included in errors as it may contain the secret
+	var output credentialsCommandOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return "", "", fmt.Errorf("output is not a JSON object with client_id and client_secret")
+	}
+	if output.ClientID == "" || output.ClientSecret == "" {
+		return "", "", fmt.Errorf("output does not contain client_id and client_secret")
+	}
+	return output.ClientID, output.ClientSecret, nil
+}
@@ -1,10 +1,21 @@
 package genesyscloud
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
 )
 
 // providerFactories are used to instantiate a provider during acceptance testing.
@@ -22,6 +33,341 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProviderCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Credentials command test uses a POSIX shell")
+	}
+	newProviderData := func(config map[string]interface{}) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, New("0.1.0")().Schema, config)
+	}
+
+	// An access token takes precedence over the OAuthClient attributes
+	credentials, diagErr := getProviderCredentials(newProviderData(map[string]interface{}{
+		"access_token":       "token",
+		"oauthclient_id":     "id",
+		"oauthclient_secret": "secret",
+	}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if credentials.AccessToken != "token" || credentials.ClientID != "" {
+		t.Errorf("Expected the access token to be used. Found %+v", credentials)
+	}
+
+	credentials, diagErr = getProviderCredentials(newProviderData(map[string]interface{}{
+		"oauthclient_credentials_command": `echo '{"client_id": "command-id", "client_secret": "command-secret"}'`,
+	}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if credentials.ClientID != "command-id" || credentials.ClientSecret != "command-secret" {
+		t.Errorf("Expected the credentials from the command to be used. Found %+v", credentials)
+	}
+
+	for _, command := range []string{"echo not-json", `echo '{"client_id": "id"}'`, "echo failed >&2; exit 1"} {
+		if _, _, err := runCredentialsCommand(command); err == nil {
+			t.Errorf("Expected an error for credentials command %s", command)
+		}
+	}
+}
+
+func TestProviderRetryPolicy(t *testing.T) {
+	newProviderData := func(config map[string]interface{}) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, New("0.1.0")().Schema, config)
+	}
+
+	policy, diagErr := getRetryPolicy(newProviderData(map[string]interface{}{}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if *policy != defaultRetryPolicy {
+		t.Errorf("Expected the default policy without a retry block. Found %+v", policy)
+	}
+
+	policy, diagErr = getRetryPolicy(newProviderData(map[string]interface{}{
+		"retry": []interface{}{map[string]interface{}{
+			"max_attempts":                         3,
+			"min_backoff_ms":                       500,
+			"eventual_consistency_timeout_seconds": 60,
+		}},
+	}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	expected := retryPolicy{
+		MaxAttempts:                3,
+		SDKMaxRetries:              2,
+		MinBackoff:                 500 * time.Millisecond,
+		MaxBackoff:                 defaultRetryPolicy.MaxBackoff,
+		EventualConsistencyTimeout: time.Minute,
+	}
+	if *policy != expected {
+		t.Errorf("Expected policy %+v. Found %+v", expected, policy)
+	}
+
+	// The attempts keep their defaults when max_attempts is not set
+	policy, diagErr = getRetryPolicy(newProviderData(map[string]interface{}{
+		"retry": []interface{}{map[string]interface{}{"jitter_ms": 100}},
+	}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if policy.MaxAttempts != defaultRetryPolicy.MaxAttempts || policy.SDKMaxRetries != defaultRetryPolicy.SDKMaxRetries {
+		t.Errorf("Expected the default attempts without max_attempts. Found %+v", policy)
+	}
+
+	if _, diagErr := getRetryPolicy(newProviderData(map[string]interface{}{
+		"retry": []interface{}{map[string]interface{}{
+			"min_backoff_ms": 5000,
+			"max_backoff_ms": 1000,
+		}},
+	})); diagErr == nil {
+		t.Error("Expected an error for a min backoff greater than the max backoff")
+	}
+
+	// retryWhen uses the policy of the context and stops waiting when the context is cancelled
+	ctx, cancel := context.WithCancel(withRetryPolicy(context.Background(), &retryPolicy{MaxAttempts: 3, MinBackoff: time.Hour}))
+	calls := 0
+	start := time.Now()
+	diagErr = retryWhen(ctx, isStatus404, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+		calls++
+		cancel()
+		return &platformclientv2.APIResponse{StatusCode: http.StatusNotFound}, diag.Errorf("API Error: 404")
+	})
+	if diagErr == nil || calls != 1 || time.Since(start) > time.Minute {
+		t.Errorf("Expected retries to stop when the context is cancelled. Found %d calls after %v", calls, time.Since(start))
+	}
+
+	calls = 0
+	diagErr = retryWhen(withRetryPolicy(context.Background(), &retryPolicy{MaxAttempts: 3}), isStatus404, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+		calls++
+		return &platformclientv2.APIResponse{StatusCode: http.StatusNotFound}, diag.Errorf("API Error: 404")
+	})
+	if diagErr == nil || calls != 3 {
+		t.Errorf("Expected 3 attempts. Found %d", calls)
+	}
+
+	// The eventual consistency timeout overrides the timeout of withRetries
+	ctx = withRetryPolicy(context.Background(), &retryPolicy{EventualConsistencyTimeout: time.Second})
+	start = time.Now()
+	diagErr = withRetries(ctx, time.Hour, func() *resource.RetryError {
+		return resource.RetryableError(fmt.Errorf("not found"))
+	})
+	if diagErr == nil || time.Since(start) > time.Minute {
+		t.Errorf("Expected withRetries to time out after the eventual consistency timeout. Found %v after %v", diagErr, time.Since(start))
+	}
+
+	// The SDK backoff includes the jitter and the retries of the policy
+	policy = &retryPolicy{MaxAttempts: 10, SDKMaxRetries: 20, MinBackoff: time.Second, MaxBackoff: 30 * time.Second, Jitter: time.Second}
+	for i := 0; i < 10; i++ {
+		retryConfig := policy.sdkRetryConfiguration(nil)
+		jitter := retryConfig.RetryWaitMin - policy.MinBackoff
+		if jitter < 0 || jitter > policy.Jitter || retryConfig.RetryWaitMax != policy.MaxBackoff+jitter || retryConfig.RetryMax != 20 {
+			t.Errorf("Expected an SDK backoff of %v-%v with up to %v jitter and 20 retries. Found %+v", policy.MinBackoff, policy.MaxBackoff, policy.Jitter, retryConfig)
+		}
+	}
+}
+
+func TestProviderBasePaths(t *testing.T) {
+	providerSchema := New("0.1.0")().Schema
+
+	basePath, diagErr := getProviderBasePath(schema.TestResourceDataRaw(t, providerSchema, map[string]interface{}{
+		"aws_region":    "us-east-1",
+		"sdk_base_path": "http://localhost:8080/",
+	}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if basePath != "http://localhost:8080" {
+		t.Errorf("Expected sdk_base_path to override the region. Found %s", basePath)
+	}
+	if domain := getBasePathDomain("https://api.example.pure.cloud"); domain != "example.pure.cloud" {
+		t.Errorf("Unexpected domain %s", domain)
+	}
+
+	// Clients are authorized with the login service at the auth base path
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if r.URL.Path != "/oauth/token" || !ok || clientID != "id" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "token", "expires_in": 86400}`))
+	}))
+	defer server.Close()
+
+	credentials, diagErr := getProviderCredentials(schema.TestResourceDataRaw(t, providerSchema, map[string]interface{}{
+		"oauthclient_id":     "id",
+		"oauthclient_secret": "secret",
+		"auth_base_path":     server.URL + "/",
+	}))
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	config := platformclientv2.NewConfiguration()
+	config.BasePath = "http://api.invalid"
+	if _, diagErr := credentials.authorize(config); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if config.AccessToken != "token" {
+		t.Errorf("Expected the access token from the login service. Found %s", config.AccessToken)
+	}
+
+	credentials.ClientSecret = "wrong"
+	if _, diagErr := credentials.authorize(platformclientv2.NewConfiguration()); diagErr == nil {
+		t.Error("Expected an error for invalid client credentials")
+	}
+}
+
+func TestProviderMultipleOrgs(t *testing.T) {
+	var tokensIssued int32
+	newOrgServer := func(org string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/oauth/token":
+				atomic.AddInt32(&tokensIssued, 1)
+				w.Write([]byte(`{"access_token": "token-` + org + `"}`))
+			case "/api/v2/authorization/divisions/home":
+				if r.Header.Get("Authorization") != "Bearer token-"+org {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{"id": "home-` + org + `"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+
+	// Aliased providers configured for different orgs do not share clients or caches
+	for _, org := range []string{"prod", "dr"} {
+		server := newOrgServer(org)
+		defer server.Close()
+
+		atomic.StoreInt32(&tokensIssued, 0)
+		provider := New("0.1.0")()
+		diagErr := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+			"oauthclient_id":     "id",
+			"oauthclient_secret": "secret",
+			"sdk_base_path":      server.URL,
+			"auth_base_path":     server.URL,
+			"token_pool_size":    2,
+		}))
+		if diagErr.HasError() {
+			t.Fatal(diagErr)
+		}
+		meta := provider.Meta().(*providerMeta)
+		if issued := atomic.LoadInt32(&tokensIssued); issued != 0 {
+			t.Errorf("Expected clients to be authorized when first acquired, not when the provider is configured. Found %d tokens issued", issued)
+		}
+
+		clientConfig, diagErr := meta.ClientPool.acquire()
+		if diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		if clientConfig.BasePath != server.URL || clientConfig.AccessToken != "token-"+org {
+			t.Errorf("Expected pooled clients for the %s org. Found %s with token %s", org, clientConfig.BasePath, clientConfig.AccessToken)
+		}
+		meta.ClientPool.release(clientConfig)
+
+		// The home division is queried with a pooled client like any other request
+		var homeDivisionID string
+		read := readWithPooledClient(func(_ context.Context, _ *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			var diagErr diag.Diagnostics
+			homeDivisionID, diagErr = getHomeDivisionID(meta)
+			return diagErr
+		})
+		if diagErr := read(context.Background(), schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{}), meta); diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		if homeDivisionID != "home-"+org {
+			t.Errorf("Expected the home division of the %s org. Found %s", org, homeDivisionID)
+		}
+	}
+}
+
+func TestProviderExpectedOrg(t *testing.T) {
+	orgResponse := `{"id": "org-dev", "name": "Dev", "domain": "dev", "defaultCountryCode": "US", "defaultLanguage": "en-us"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/token":
+			w.Write([]byte(`{"access_token": "token"}`))
+		case "/api/v2/organizations/me":
+			w.Write([]byte(orgResponse))
+		case "/api/v2/authorization/divisions/home":
+			w.Write([]byte(`{"id": "home-dev"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	configure := func(expected map[string]interface{}) (*schema.Provider, diag.Diagnostics) {
+		config := map[string]interface{}{
+			"oauthclient_id":     "id",
+			"oauthclient_secret": "secret",
+			"sdk_base_path":      server.URL,
+			"auth_base_path":     server.URL,
+		}
+		for attr, val := range expected {
+			config[attr] = val
+		}
+		provider := New("0.1.0")()
+		return provider, provider.Configure(context.Background(), terraform.NewResourceConfigRaw(config))
+	}
+
+	for _, expected := range []map[string]interface{}{
+		{"expected_org_id": "org-prod"},
+		{"expected_org_name": "Prod"},
+		{"expected_org_id": "org-dev", "expected_org_name": "Prod"},
+	} {
+		if _, diagErr := configure(expected); !diagErr.HasError() {
+			t.Errorf("Expected the provider to refuse credentials of another org than %v", expected)
+		}
+	}
+
+	provider, diagErr := configure(map[string]interface{}{"expected_org_id": "org-dev", "expected_org_name": "Dev"})
+	if diagErr.HasError() {
+		t.Fatal(diagErr)
+	}
+
+	// The organization data source exposes the org of the credentials
+	dataSource := provider.DataSourcesMap["genesyscloud_organization"]
+	d := dataSource.TestResourceData()
+	if diagErr := dataSource.ReadContext(context.Background(), d, provider.Meta()); diagErr.HasError() {
+		t.Fatal(diagErr)
+	}
+	if d.Id() != "org-dev" {
+		t.Errorf("Expected the ID of the org. Found %s", d.Id())
+	}
+	expected := map[string]string{
+		"name":                 "Dev",
+		"domain":               "dev",
+		"default_country_code": "US",
+		"default_language":     "en-us",
+		"home_division_id":     "home-dev",
+	}
+	for attr, val := range expected {
+		if found := d.Get(attr).(string); found != val {
+			t.Errorf("Expected %s to be %s. Found %s", attr, val, found)
+		}
+	}
+
+	// An org without an ID or name is an error instead of a panic
+	for _, orgResponse = range []string{`{"name": "Dev"}`, `{"id": "org-dev"}`} {
+		if _, diagErr := configure(map[string]interface{}{"expected_org_id": "org-dev"}); !diagErr.HasError() {
+			t.Errorf("Expected an error for the org %s", orgResponse)
+		}
+		if diagErr := dataSource.ReadContext(context.Background(), dataSource.TestResourceData(), provider.Meta()); !diagErr.HasError() {
+			t.Errorf("Expected the data source to fail for the org %s", orgResponse)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("GENESYSCLOUD_OAUTHCLIENT_ID"); v == "" {
 		t.Fatal("Missing env GENESYSCLOUD_OAUTHCLIENT_ID")
@@ -32,4 +378,7 @@ func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("GENESYSCLOUD_REGION"); v == "" {
 		os.Setenv("GENESYSCLOUD_REGION", "dca") // Default to dev environment
 	}
+	if err := authorizeTestDefaultConfig(); err != nil {
+		t.Fatalf("Failed to authorize the test SDK client: %s", diagnosticsString(err))
+	}
 }
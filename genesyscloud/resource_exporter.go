@@ -2,10 +2,12 @@ package genesyscloud
 
 import (
 	"context"
+	"fmt"
 	"hash/fnv"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -17,13 +19,37 @@ type ResourceMeta struct {
 
 	// Prefix to add to the ID when reading state
 	IdPrefix string
+
+	// Time the object was last modified, if the API exposes it. Objects that have not been modified since
+	// an incremental export's modified_since time are not read again.
+	DateModified *time.Time
+
+	// Version of the object, if the API exposes it instead of a modification date. Objects whose version has not changed
+	// since a previous export are not read again by an incremental export.
+	Version *int
+
+	// Division of the object. This must be set by the GetResourcesFunc of division-aware types.
+	DivisionID string
 }
 
 // ResourceIDMetaMap is a map of IDs to ResourceMeta
 type ResourceIDMetaMap map[string]*ResourceMeta
 
-// GetAllResourcesFunc is a method that returns all resource IDs
-type GetAllResourcesFunc func(context.Context) (ResourceIDMetaMap, diag.Diagnostics)
+// GetAllResourcesFunc is a method that returns all resource IDs. It is called with the provider meta.
+type GetAllResourcesFunc func(context.Context, interface{}) (ResourceIDMetaMap, diag.Diagnostics)
+
+// GetResourceNameFunc is a method that returns the name of an object from its ID. It is called with the provider meta.
+type GetResourceNameFunc func(context.Context, string, interface{}) (string, diag.Diagnostics)
+
+// ExportFilesFunc is a method that writes the files used by a resource to the export directory. It is called with the
+// resource ID, the exported resource name, the sanitized config map, the export directory, and the provider meta.
+// The config map should be updated to reference the files with paths relative to the export directory.
+type ExportFilesFunc func(context.Context, string, string, jsonMap, string, interface{}) diag.Diagnostics
+
+// RestoreFilesFunc is a method that sets the attributes of an exported config that reference exported files to their
+// values in the config read from the org. It is called before the exported config is compared with the org, so the
+// paths of the exported files are not reported as changes.
+type RestoreFilesFunc func(exported map[string]interface{}, current map[string]interface{})
 
 // RefAttrSettings contains behavior settings for references
 type RefAttrSettings struct {
@@ -33,6 +59,31 @@ type RefAttrSettings struct {
 
 	// Values that may be set that should not be treated as IDs
 	AltValues []string
+
+	// Data source used to look up the referenced object by name when RefType is not being exported, e.g. genesyscloud_flow.
+	// The data source must have a name lookup in getDataSourceNameFuncs.
+	DataSource string
+}
+
+// VariableAttrSettings contains behavior settings for attributes exported as variables
+type VariableAttrSettings struct {
+
+	// Sensitive variables are declared as sensitive and their values are not written to the tfvars file by default.
+	// Attributes with a sensitive schema are always treated as sensitive.
+	Sensitive bool
+}
+
+// AttributeReplacement rewrites the values of an attribute that match a regular expression
+type AttributeReplacement struct {
+
+	// Attribute path in the same form as ExcludedAttributes. Values of nested attributes are also replaced.
+	Attribute string
+
+	// Expression to match in the attribute value
+	Pattern *regexp.Regexp
+
+	// Replacement for each match. It may contain $1 style references to groups in the pattern.
+	Replacement string
 }
 
 // ResourceExporter is an interface to implement for resources that can be exported
@@ -58,20 +109,117 @@ type ResourceExporter struct {
 	// Map of resource id->names. This is set after a call to loadSanitizedResourceMap
 	SanitizedResourceMap ResourceIDMetaMap
 
+	// IDs of resources that have not been modified since a previous export and are not read again.
+	// This is set by the export configuration.
+	UnmodifiedIDs map[string]bool
+
+	// Map of resource id->names from a previous export. These names are kept so renamed objects keep their address.
+	// This is set by the export configuration.
+	ExportedNames map[string]string
+
+	// IDs of all objects returned by GetResourcesFunc before filtering. This is set after a call to loadSanitizedResourceMap
+	ListedIDs map[string]bool
+
 	// List of attributes to exclude from config. This is set by the export configuration.
 	ExcludedAttributes []string
+
+	// Rules that rewrite the values of attributes in the config. This is set by the export configuration.
+	ReplacedAttributes []*AttributeReplacement
+
+	// A map of top-level attributes that are exported as variables when requested by the export configuration.
+	// These should be values that are likely to differ between orgs, such as emails and phone numbers.
+	VariableAttrs map[string]*VariableAttrSettings
+
+	// Optional method to export files used by each resource, such as audio files, along with the config
+	ExportFilesFunc ExportFilesFunc
+
+	// Method to undo the changes of ExportFilesFunc when comparing the exported config with the org. Should be set with ExportFilesFunc.
+	RestoreFilesFunc RestoreFilesFunc
+
+	// List of attributes that contain JSON strings. These are written as jsonencode() expressions when exporting HCL
+	JsonEncodeAttributes []string
+
+	// Objects of division-aware types belong to a division, and their GetResourcesFunc sets the DivisionID of each object.
+	// Objects of these types can be filtered by division.
+	DivisionAware bool
+
+	// Resources of division-aware types are only exported if they are in one of these divisions. This is set by the export configuration.
+	DivisionIDs []string
+
+	// Resources are only exported if their name matches one of these expressions. This is set by the export configuration.
+	IncludeNameFilters []*regexp.Regexp
+
+	// Resources are not exported if their name matches one of these expressions. This is set by the export configuration.
+	ExcludeNameFilters []*regexp.Regexp
 }
 
-func (r *ResourceExporter) loadSanitizedResourceMap(ctx context.Context) diag.Diagnostics {
-	result, err := r.GetResourcesFunc(ctx)
+func (r *ResourceExporter) loadSanitizedResourceMap(ctx context.Context, meta interface{}) diag.Diagnostics {
+	result, err := r.GetResourcesFunc(ctx, meta)
 	if err != nil {
 		return err
 	}
 	r.SanitizedResourceMap = result
+	r.ListedIDs = make(map[string]bool, len(result))
+	for id := range result {
+		r.ListedIDs[id] = true
+	}
+
+	// Filter before sanitizing so expressions are matched against the original names
+	r.filterResourceNames()
+	r.filterResourceDivisions()
 	sanitizeResourceNames(r.SanitizedResourceMap)
+
+	// Names of objects that no longer exist can be reused
+	previousNames := make(map[string]string, len(r.ExportedNames))
+	for id, name := range r.ExportedNames {
+		if r.ListedIDs[id] {
+			previousNames[id] = name
+		}
+	}
+	applyResourceNames(r.SanitizedResourceMap, previousNames)
 	return nil
 }
 
+func (r *ResourceExporter) filterResourceNames() {
+	for id, meta := range r.SanitizedResourceMap {
+		if !r.isResourceNameIncluded(meta.Name) {
+			delete(r.SanitizedResourceMap, id)
+		}
+	}
+}
+
+func (r *ResourceExporter) filterResourceDivisions() {
+	if !r.DivisionAware || len(r.DivisionIDs) == 0 {
+		return
+	}
+	for id, meta := range r.SanitizedResourceMap {
+		if !stringInSlice(meta.DivisionID, r.DivisionIDs) {
+			delete(r.SanitizedResourceMap, id)
+		}
+	}
+}
+
+func (r *ResourceExporter) isResourceNameIncluded(name string) bool {
+	if len(r.IncludeNameFilters) > 0 {
+		matched := false
+		for _, filter := range r.IncludeNameFilters {
+			if filter.MatchString(name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, filter := range r.ExcludeNameFilters {
+		if filter.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *ResourceExporter) getRefAttrSettings(attribute string) *RefAttrSettings {
 	if r.RefAttrs == nil {
 		return nil
@@ -83,6 +231,10 @@ func (r *ResourceExporter) allowZeroValues(attribute string) bool {
 	return stringInSlice(attribute, r.AllowZeroValues)
 }
 
+func (r *ResourceExporter) isJsonEncodeAttribute(attribute string) bool {
+	return stringInSlice(attribute, r.JsonEncodeAttributes)
+}
+
 func (r *ResourceExporter) addExcludedAttribute(attribute string) {
 	r.ExcludedAttributes = append(r.ExcludedAttributes, attribute)
 }
@@ -97,6 +249,16 @@ func (r *ResourceExporter) isAttributeExcluded(attribute string) bool {
 	return false
 }
 
+// Applies each replacement rule for the attribute to a value, in the order the rules were added
+func (r *ResourceExporter) replaceAttributeValue(attribute string, value string) string {
+	for _, replacement := range r.ReplacedAttributes {
+		if replacement.Attribute == attribute || strings.HasPrefix(attribute, replacement.Attribute+".") {
+			value = replacement.Pattern.ReplaceAllString(value, replacement.Replacement)
+		}
+	}
+	return value
+}
+
 func (r *ResourceExporter) removeIfMissing(attribute string, config map[string]interface{}) bool {
 	if attrs, ok := r.RemoveIfMissing[attribute]; ok {
 		// Check if all required inner attributes are missing
@@ -177,6 +339,31 @@ func getAvailableExporterTypes() []string {
 	return types
 }
 
+// Name lookups for data sources that references can fall back to when the referenced type is not exported
+func getDataSourceNameFuncs() map[string]GetResourceNameFunc {
+	return map[string]GetResourceNameFunc{
+		// Add new data sources that can be referenced by exported resources here
+		"genesyscloud_flow":   getNameWithPooledClient(getFlowName),
+		"genesyscloud_script": getNameWithPooledClient(getScriptName),
+	}
+}
+
+// Separates the resource type from a name expression in export filters, e.g. genesyscloud_routing_queue::^Sales_
+const resourceFilterSeparator = "::"
+
+// Splits a resource filter into its resource type and name expression. The expression is nil if only a type was specified.
+func parseResourceFilter(filter string) (string, *regexp.Regexp, error) {
+	parts := strings.SplitN(filter, resourceFilterSeparator, 2)
+	if len(parts) == 1 {
+		return parts[0], nil, nil
+	}
+	nameFilter, err := regexp.Compile(parts[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("Invalid name expression in filter %s: %v", filter, err)
+	}
+	return parts[0], nameFilter, nil
+}
+
 func escapeRune(s string) string {
 	// Always replace with an underscore for readability. The appended hash will help ensure uniqueness
 	return "_"
@@ -0,0 +1,179 @@
+package genesyscloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Matches the data source reference expressions generated by resolveReference, e.g. ${data.genesyscloud_flow.my_flow.id}
+var dataSourceRefExpression = regexp.MustCompile(`^\$\{data\.([0-9A-Za-z_-]+)\.([0-9A-Za-z_-]+)\.id\}$`)
+
+// Data blocks kept from an existing export are keyed by this prefix and their name, as the ID of the object is not known
+const existingDataSourceKeyPrefix = "name:"
+
+// dataSourceMeta describes a data block written to look up a referenced object by name
+type dataSourceMeta struct {
+	// Name of the data block in the exported config
+	Name string
+
+	// Name of the object in Genesys Cloud
+	ObjectName string
+}
+
+// exportedDataSources is a map of data source types to the IDs of referenced objects and their data blocks
+type exportedDataSources map[string]map[string]*dataSourceMeta
+
+// Returns a reference expression to the data block for an object, or an empty string if there is no data block
+func (d exportedDataSources) getReference(dataSourceType string, id string) string {
+	if meta := d[dataSourceType][id]; meta != nil {
+		return fmt.Sprintf("${data.%s.%s.id}", dataSourceType, meta.Name)
+	}
+	return ""
+}
+
+// Adds a data block from an existing export unless a data block with the same name has already been added
+func (d exportedDataSources) addExisting(dataSourceType string, name string, objectName string) {
+	for _, meta := range d[dataSourceType] {
+		if meta.Name == name {
+			return
+		}
+	}
+	if d[dataSourceType] == nil {
+		d[dataSourceType] = make(map[string]*dataSourceMeta)
+	}
+	d[dataSourceType][existingDataSourceKeyPrefix+name] = &dataSourceMeta{Name: name, ObjectName: objectName}
+}
+
+// Returns the data blocks in the same structure as resources in a JSON config
+func (d exportedDataSources) jsonMaps() map[string]map[string]jsonMap {
+	result := make(map[string]map[string]jsonMap)
+	for dataSourceType, idMetaMap := range d {
+		result[dataSourceType] = make(map[string]jsonMap)
+		for _, meta := range idMetaMap {
+			result[dataSourceType][meta.Name] = jsonMap{"name": escapeString(meta.ObjectName)}
+		}
+	}
+	return result
+}
+
+// Finds references that cannot be resolved to an exported resource but fall back to a data source, and looks up
+// the name of each referenced object. Objects that cannot be looked up are skipped so the reference is handled as before.
+func buildDataSources(
+	resources []resourceInfo,
+	configMaps []jsonMap,
+	exporters map[string]*ResourceExporter,
+	nameFuncs map[string]GetResourceNameFunc,
+	meta interface{}) exportedDataSources {
+
+	refIDs := make(map[string]map[string]bool)
+	for i, resource := range resources {
+		for _, ref := range collectReferences(exporters[resource.Type], configMaps[i], "") {
+			if ref.DataSource == "" || nameFuncs[ref.DataSource] == nil {
+				continue
+			}
+			if exporter := exporters[ref.Type]; exporter != nil && exporter.SanitizedResourceMap[ref.ID] != nil {
+				// Resolved to an exported resource
+				continue
+			}
+			if refIDs[ref.DataSource] == nil {
+				refIDs[ref.DataSource] = make(map[string]bool)
+			}
+			refIDs[ref.DataSource][ref.ID] = true
+		}
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	names := make(map[string]ResourceIDMetaMap)
+	for dataSourceType, ids := range refIDs {
+		names[dataSourceType] = make(ResourceIDMetaMap)
+		for id := range ids {
+			wg.Add(1)
+			go func(dataSourceType string, id string) {
+				defer wg.Done()
+				name, err := nameFuncs[dataSourceType](context.Background(), id, meta)
+				if err != nil || name == "" {
+					log.Printf("Unable to look up %s %s for a data source: %v", dataSourceType, id, err)
+					return
+				}
+				mutex.Lock()
+				names[dataSourceType][id] = &ResourceMeta{Name: name}
+				mutex.Unlock()
+			}(dataSourceType, id)
+		}
+	}
+	wg.Wait()
+
+	dataSources := make(exportedDataSources)
+	for dataSourceType, idMetaMap := range names {
+		objectNames := make(map[string]string, len(idMetaMap))
+		for id, meta := range idMetaMap {
+			objectNames[id] = meta.Name
+		}
+		sanitizeResourceNames(idMetaMap)
+
+		dataSources[dataSourceType] = make(map[string]*dataSourceMeta)
+		usedNames := make(map[string]bool)
+		for _, id := range sortedResourceIDs(idMetaMap) {
+			name := idMetaMap[id].Name
+			if usedNames[name] {
+				// Different objects with the same name need separate data blocks
+				name = name + "_" + getIDNameSuffix(id)
+			}
+			usedNames[name] = true
+			dataSources[dataSourceType][id] = &dataSourceMeta{Name: name, ObjectName: objectNames[id]}
+			log.Printf("Referencing %s %s with data source %s", dataSourceType, id, name)
+		}
+	}
+	return dataSources
+}
+
+// Returns the data blocks referenced by the config of a set of resources
+func getReferencedDataSources(resourceMaps map[string]jsonMap, dataSourceMaps map[string]map[string]jsonMap) map[string]map[string]jsonMap {
+	referenced := make(map[string]map[string]jsonMap)
+	var findReferences func(val interface{})
+	findReferences = func(val interface{}) {
+		switch v := val.(type) {
+		case string:
+			if matches := dataSourceRefExpression.FindStringSubmatch(v); matches != nil {
+				if dataSource := dataSourceMaps[matches[1]][matches[2]]; dataSource != nil {
+					if referenced[matches[1]] == nil {
+						referenced[matches[1]] = make(map[string]jsonMap)
+					}
+					referenced[matches[1]][matches[2]] = dataSource
+				}
+			}
+		case jsonMap:
+			for _, elem := range v {
+				findReferences(elem)
+			}
+		case map[string]interface{}:
+			for _, elem := range v {
+				findReferences(elem)
+			}
+		case []interface{}:
+			for _, elem := range v {
+				findReferences(elem)
+			}
+		}
+	}
+	for _, config := range resourceMaps {
+		findReferences(config)
+	}
+	return referenced
+}
+
+func sortedResourceIDs(idMetaMap ResourceIDMetaMap) []string {
+	ids := make([]string, 0, len(idMetaMap))
+	for id := range idMetaMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
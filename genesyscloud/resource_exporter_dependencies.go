@@ -0,0 +1,276 @@
+package genesyscloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceRef identifies a single exported resource
+type resourceRef struct {
+	Type string
+	ID   string
+}
+
+func (r resourceRef) String() string {
+	return r.Type + resourceFilterSeparator + r.ID
+}
+
+// attributeRef is a reference to another resource from an attribute
+type attributeRef struct {
+	resourceRef
+	Attribute  string
+	DataSource string
+}
+
+// Parses root resources of the form {resource_type}::{id}
+func parseRootResources(rootResources []string) ([]resourceRef, diag.Diagnostics) {
+	var refs []resourceRef
+	for _, root := range rootResources {
+		parts := strings.SplitN(root, resourceFilterSeparator, 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, diag.Errorf("Invalid root resource %s. Root resources must be of the form {resource_type}::{id}", root)
+		}
+		refs = append(refs, resourceRef{Type: parts[0], ID: parts[1]})
+	}
+	return refs, nil
+}
+
+// Returns the root resource types and every exportable type they can reference directly or indirectly
+func getDependencyResourceTypes(roots []resourceRef) []string {
+	allExporters := getResourceExporters(nil)
+
+	var resTypes []string
+	var queue []string
+	for _, root := range roots {
+		queue = append(queue, root.Type)
+	}
+	for len(queue) > 0 {
+		resType := queue[0]
+		queue = queue[1:]
+		if stringInSlice(resType, resTypes) || allExporters[resType] == nil {
+			continue
+		}
+		resTypes = append(resTypes, resType)
+		for _, refSettings := range allExporters[resType].RefAttrs {
+			queue = append(queue, refSettings.RefType)
+		}
+	}
+	return resTypes
+}
+
+// Reads the state of the root resources and every resource they reference, following the RefAttrs of each exporter.
+// Returns the resources in the dependency closure and a description of each reference that could not be followed
+// because there is no exporter for the referenced type. If report is set, resources that fail to be read are recorded
+// and skipped instead of failing the export.
+func getRootResourceDependencies(
+	roots []resourceRef,
+	provider *schema.Provider,
+	exporters map[string]*ResourceExporter,
+	meta interface{},
+	report *exportReport) ([]resourceInfo, []string, diag.Diagnostics) {
+
+	for _, root := range roots {
+		if exporters[root.Type] == nil {
+			return nil, nil, diag.Errorf("Root resource type %s is not being exported", root.Type)
+		}
+		if exporters[root.Type].SanitizedResourceMap[root.ID] == nil {
+			return nil, nil, diag.Errorf("Root resource %s not found", root)
+		}
+	}
+
+	// Cancel remaining reads if an error occurs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		resources  []resourceInfo
+		unresolved []string
+		skipped    []resourceRef
+		deleted    []resourceRef
+		mutex      sync.Mutex
+		firstErr   diag.Diagnostics
+	)
+	visited := make(map[resourceRef]bool)
+	level := roots
+	for len(level) > 0 {
+		var nextLevel []resourceRef
+		var wg sync.WaitGroup
+		for _, ref := range level {
+			if visited[ref] {
+				continue
+			}
+			visited[ref] = true
+
+			wg.Add(1)
+			go func(ref resourceRef) {
+				defer wg.Done()
+				resource, refs, err := readResourceReferences(ctx, ref, provider, exporters, meta)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err != nil && report != nil {
+					report.addResourceError(ref.Type, ref.ID, err)
+					skipped = append(skipped, ref)
+					return
+				}
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					cancel()
+					return
+				}
+				if resource == nil {
+					deleted = append(deleted, ref)
+					return
+				}
+				resources = append(resources, *resource)
+				for _, depRef := range refs {
+					if depRef.Type == "" {
+						unresolved = append(unresolved, fmt.Sprintf("%s %s references %s of an undefined type", ref, depRef.Attribute, depRef.ID))
+						continue
+					}
+					if exporters[depRef.Type] == nil && depRef.DataSource != "" {
+						log.Printf("Resource %s referenced by %s will be looked up with data source %s", depRef.resourceRef, ref, depRef.DataSource)
+						continue
+					}
+					if exporters[depRef.Type] == nil {
+						unresolved = append(unresolved, fmt.Sprintf("%s %s references %s", ref, depRef.Attribute, depRef.resourceRef))
+						continue
+					}
+					if exporters[depRef.Type].SanitizedResourceMap[depRef.ID] == nil {
+						log.Printf("Resource %s referenced by %s was not found or was filtered from the export", depRef.resourceRef, ref)
+						continue
+					}
+					nextLevel = append(nextLevel, depRef.resourceRef)
+				}
+			}(ref)
+		}
+		wg.Wait()
+
+		if firstErr != nil {
+			return nil, nil, firstErr
+		}
+		level = nextLevel
+	}
+
+	// Skipped resources are not referenced by the exported config
+	for _, ref := range skipped {
+		delete(exporters[ref.Type].SanitizedResourceMap, ref.ID)
+	}
+
+	// Deleted resources are not exported, so references to them are not resolved to a resource
+	for _, ref := range deleted {
+		delete(visited, ref)
+		delete(exporters[ref.Type].SanitizedResourceMap, ref.ID)
+	}
+
+	// Only the dependency closure will be exported
+	for resType, exporter := range exporters {
+		for id := range exporter.SanitizedResourceMap {
+			if !visited[resourceRef{Type: resType, ID: id}] {
+				delete(exporter.SanitizedResourceMap, id)
+			}
+		}
+	}
+
+	sort.Strings(unresolved)
+	for _, ref := range unresolved {
+		log.Printf("Unable to follow reference with no exporter: %s", ref)
+	}
+	return resources, unresolved, nil
+}
+
+// Reads the state of a resource and returns the references in its RefAttrs
+func readResourceReferences(
+	ctx context.Context,
+	ref resourceRef,
+	provider *schema.Provider,
+	exporters map[string]*ResourceExporter,
+	meta interface{}) (*resourceInfo, []attributeRef, diag.Diagnostics) {
+
+	exporter := exporters[ref.Type]
+	resMeta := exporter.SanitizedResourceMap[ref.ID]
+	resource := provider.ResourcesMap[ref.Type]
+	if resource == nil {
+		return nil, nil, diag.Errorf("Resource type %s not defined", ref.Type)
+	}
+	ctyType := resource.CoreConfigSchema().ImpliedType()
+
+	instanceState, err := getResourceState(ctx, resource, ref.ID, resMeta, meta)
+	if err != nil {
+		return nil, nil, diag.Errorf("Failed to get state for %s instance %s: %v", ref.Type, ref.ID, err)
+	}
+	if instanceState == nil {
+		log.Printf("Resource %s no longer exists. Skipping.", resMeta.Name)
+		return nil, nil, nil
+	}
+
+	configMap, diagErr := instanceStateToJSONMap(instanceState, ctyType)
+	if diagErr != nil {
+		return nil, nil, diagErr
+	}
+
+	return &resourceInfo{
+		State:    instanceState,
+		Name:     resMeta.Name,
+		Type:     ref.Type,
+		CtyType:  ctyType,
+		ImportID: resMeta.IdPrefix + ref.ID,
+	}, collectReferences(exporter, configMap, ""), nil
+}
+
+// Collects the references to other resources in a config map using the same attribute paths as sanitizeConfigMap
+func collectReferences(exporter *ResourceExporter, configMap map[string]interface{}, prevAttr string) []attributeRef {
+	var refs []attributeRef
+	for key, val := range configMap {
+		currAttr := key
+		wildcardAttr := "*"
+		if prevAttr != "" {
+			currAttr = prevAttr + "." + key
+			wildcardAttr = prevAttr + "." + "*"
+		}
+		if currAttr == "id" {
+			continue
+		}
+
+		switch v := val.(type) {
+		case map[string]interface{}:
+			refs = append(refs, collectReferences(exporter, v, currAttr)...)
+		case []interface{}:
+			for _, elem := range v {
+				switch e := elem.(type) {
+				case map[string]interface{}:
+					refs = append(refs, collectReferences(exporter, e, currAttr)...)
+				case string:
+					refs = appendReference(refs, exporter.getRefAttrSettings(currAttr), currAttr, e)
+				}
+			}
+		case string:
+			refSettings := exporter.getRefAttrSettings(currAttr)
+			if refSettings == nil {
+				refSettings = exporter.getRefAttrSettings(wildcardAttr)
+			}
+			refs = appendReference(refs, refSettings, currAttr, v)
+		}
+	}
+	return refs
+}
+
+func appendReference(refs []attributeRef, refSettings *RefAttrSettings, attribute string, refID string) []attributeRef {
+	if refSettings == nil || refID == "" || stringInSlice(refID, refSettings.AltValues) {
+		return refs
+	}
+	return append(refs, attributeRef{
+		resourceRef: resourceRef{Type: refSettings.RefType, ID: refID},
+		Attribute:   attribute,
+		DataSource:  refSettings.DataSource,
+	})
+}
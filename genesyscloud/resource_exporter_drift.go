@@ -0,0 +1,675 @@
+package genesyscloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const defaultDriftReportFile = "drift_report"
+
+// Matches variable references in an exported config, e.g. ${var.user_john_doe_email}
+var variableRefExpression = regexp.MustCompile(`^\$\{var\.([0-9A-Za-z_-]+)\}$`)
+
+// Matches module output references in an exported config, e.g. ${module.routing_skill.skill_1_id}
+var moduleOutputRefExpression = regexp.MustCompile(`^\$\{module\.([0-9A-Za-z_-]+)\.([0-9A-Za-z_-]+)\}$`)
+
+// Functions that may be called in an exported HCL config
+var exportedConfigFunctions = map[string]function.Function{
+	"jsonencode": stdlib.JSONEncodeFunc,
+}
+
+type driftReport struct {
+	Directory string          `json:"directory"`
+	Added     []driftResource `json:"added"`
+	Removed   []driftResource `json:"removed"`
+	Modified  []driftResource `json:"modified"`
+}
+
+type driftResource struct {
+	Type       string               `json:"type"`
+	Name       string               `json:"name"`
+	Attributes []driftAttributeDiff `json:"attributes,omitempty"`
+}
+
+type driftAttributeDiff struct {
+	Path     string      `json:"path"`
+	Exported interface{} `json:"exported"`
+	Current  interface{} `json:"current"`
+}
+
+func (r *driftReport) hasDrift() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Modified) > 0
+}
+
+// exportedModule is the config loaded from a single directory of an existing export
+type exportedModule struct {
+	Resources   map[string]map[string]map[string]interface{}
+	DataSources map[string]map[string]map[string]interface{}
+	Modules     map[string]map[string]interface{}
+	Outputs     map[string]interface{}
+}
+
+// Compares the resources read from the org with the config already exported to the directory and
+// writes a JSON and markdown report of the differences. The exported config is not modified.
+func writeDriftReport(
+	resourceTypeJSONMaps map[string]map[string]jsonMap,
+	exporters map[string]*ResourceExporter,
+	directory string) (*driftReport, diag.Diagnostics) {
+
+	exported, _, diagErr := loadExportedResources(directory, true)
+	if diagErr != nil {
+		return nil, diagErr
+	}
+
+	// Only compare the types being exported
+	for resType := range exported {
+		if exporters[resType] == nil {
+			delete(exported, resType)
+		}
+	}
+
+	current := make(map[string]map[string]map[string]interface{})
+	for resType, resourceMaps := range resourceTypeJSONMaps {
+		current[resType] = make(map[string]map[string]interface{})
+		for resName, config := range resourceMaps {
+			current[resType][resName] = normalizeConfigValue(config).(map[string]interface{})
+			// Types that are not exported may be kept from an existing incremental export
+			if exporter := exporters[resType]; exporter != nil && exporter.RestoreFilesFunc != nil && exported[resType][resName] != nil {
+				exporter.RestoreFilesFunc(exported[resType][resName], current[resType][resName])
+			}
+		}
+	}
+
+	report := buildDriftReport(exported, current)
+	report.Directory = directory
+
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	jsonPath := filepath.Join(directory, defaultDriftReportFile+".json")
+	log.Printf("Writing drift report to %s", jsonPath)
+	if err := writeToFile(reportJSON, jsonPath); err != nil {
+		return nil, err
+	}
+
+	markdownPath := filepath.Join(directory, defaultDriftReportFile+".md")
+	log.Printf("Writing drift report to %s", markdownPath)
+	if err := writeToFile([]byte(driftReportMarkdown(report)), markdownPath); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func buildDriftReport(exported map[string]map[string]map[string]interface{}, current map[string]map[string]map[string]interface{}) *driftReport {
+	report := &driftReport{
+		Added:    []driftResource{},
+		Removed:  []driftResource{},
+		Modified: []driftResource{},
+	}
+
+	resTypes := make(map[string]bool)
+	for resType := range exported {
+		resTypes[resType] = true
+	}
+	for resType := range current {
+		resTypes[resType] = true
+	}
+
+	for resType := range resTypes {
+		for resName, config := range current[resType] {
+			exportedConfig, ok := exported[resType][resName]
+			if !ok {
+				report.Added = append(report.Added, driftResource{Type: resType, Name: resName})
+				continue
+			}
+			if diffs := diffConfigValues("", exportedConfig, config); len(diffs) > 0 {
+				report.Modified = append(report.Modified, driftResource{Type: resType, Name: resName, Attributes: diffs})
+			}
+		}
+		for resName := range exported[resType] {
+			if _, ok := current[resType][resName]; !ok {
+				report.Removed = append(report.Removed, driftResource{Type: resType, Name: resName})
+			}
+		}
+	}
+
+	for _, resources := range [][]driftResource{report.Added, report.Removed, report.Modified} {
+		sort.Slice(resources, func(i, j int) bool {
+			if resources[i].Type != resources[j].Type {
+				return resources[i].Type < resources[j].Type
+			}
+			return resources[i].Name < resources[j].Name
+		})
+	}
+	return report
+}
+
+// Returns the attribute-level differences between two config values. Lists of objects with the same
+// length are compared by element, and JSON strings are compared by their decoded values.
+func diffConfigValues(path string, exported interface{}, current interface{}) []driftAttributeDiff {
+	if str, ok := exported.(string); ok && variableRefExpression.MatchString(str) {
+		// The value of this variable was not exported, e.g. a sensitive variable
+		return nil
+	}
+
+	switch e := exported.(type) {
+	case map[string]interface{}:
+		if c, ok := current.(map[string]interface{}); ok {
+			var diffs []driftAttributeDiff
+			for _, key := range sortedKeys(mergeKeys(e, c)) {
+				diffs = append(diffs, diffConfigValues(joinAttributePath(path, key), e[key], c[key])...)
+			}
+			return diffs
+		}
+	case []interface{}:
+		if c, ok := current.([]interface{}); ok && len(e) == len(c) && containsObjects(e) {
+			var diffs []driftAttributeDiff
+			for i := range e {
+				diffs = append(diffs, diffConfigValues(joinAttributePath(path, strconv.Itoa(i)), e[i], c[i])...)
+			}
+			return diffs
+		}
+	case string:
+		if c, ok := current.(string); ok && jsonStringsEqual(e, c) {
+			return nil
+		}
+	}
+
+	if reflect.DeepEqual(exported, current) {
+		return nil
+	}
+	return []driftAttributeDiff{{Path: path, Exported: exported, Current: current}}
+}
+
+func mergeKeys(a map[string]interface{}, b map[string]interface{}) map[string]interface{} {
+	keys := make(map[string]interface{}, len(a)+len(b))
+	for k := range a {
+		keys[k] = nil
+	}
+	for k := range b {
+		keys[k] = nil
+	}
+	return keys
+}
+
+func joinAttributePath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func containsObjects(list []interface{}) bool {
+	for _, elem := range list {
+		if _, ok := elem.(map[string]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Compares strings containing JSON objects or arrays by value, since exported JSON may be formatted differently
+func jsonStringsEqual(a string, b string) bool {
+	var aVal, bVal interface{}
+	if err := json.Unmarshal([]byte(unescapeString(a)), &aVal); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(unescapeString(b)), &bVal); err != nil {
+		return false
+	}
+	switch aVal.(type) {
+	case map[string]interface{}, []interface{}:
+		return reflect.DeepEqual(aVal, bVal)
+	}
+	return false
+}
+
+// Converts a config value to plain JSON types and removes null attributes, which may be omitted from an exported config
+func normalizeConfigValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case jsonMap:
+		return normalizeConfigValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			if elem != nil {
+				result[key] = normalizeConfigValue(elem)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = normalizeConfigValue(elem)
+		}
+		return result
+	case int:
+		return float64(v)
+	}
+	return val
+}
+
+// Loads the resources and data blocks from the config files of an existing export. Module inputs and
+// variables are resolved so that resources are compared by their exported values, and references
+// passed between modules are loaded as references to the exported resources.
+// If normalize is set, null attributes are removed and values are converted to plain JSON types.
+func loadExportedResources(directory string, normalize bool) (map[string]map[string]map[string]interface{}, map[string]map[string]map[string]interface{}, diag.Diagnostics) {
+	rootModule, diagErr := loadExportedModule(directory)
+	if diagErr != nil {
+		return nil, nil, diagErr
+	}
+	rootVariables, diagErr := loadExportedVariableValues(directory)
+	if diagErr != nil {
+		return nil, nil, diagErr
+	}
+
+	childModules := make(map[string]*exportedModule)
+	for moduleName, settings := range rootModule.Modules {
+		source, _ := settings["source"].(string)
+		if !strings.HasPrefix(source, "./") {
+			log.Printf("Skipping module %s with non-local source %s", moduleName, source)
+			continue
+		}
+		childModules[moduleName], diagErr = loadExportedModule(filepath.Join(directory, source))
+		if diagErr != nil {
+			return nil, nil, diagErr
+		}
+	}
+
+	// Resolves a reference to a root variable or a child module output
+	resolveRoot := func(val interface{}) interface{} {
+		str, ok := val.(string)
+		if !ok {
+			return val
+		}
+		if matches := variableRefExpression.FindStringSubmatch(str); matches != nil {
+			if value, ok := rootVariables[matches[1]]; ok {
+				return value
+			}
+		}
+		if matches := moduleOutputRefExpression.FindStringSubmatch(str); matches != nil {
+			if childModule := childModules[matches[1]]; childModule != nil {
+				if output, ok := childModule.Outputs[matches[2]].(map[string]interface{}); ok {
+					return output["value"]
+				}
+			}
+		}
+		return val
+	}
+
+	resources := make(map[string]map[string]map[string]interface{})
+	dataSources := make(map[string]map[string]map[string]interface{})
+	addResources := func(module *exportedModule, resolve func(interface{}) interface{}) {
+		for resType, resourceMaps := range module.Resources {
+			if resources[resType] == nil {
+				resources[resType] = make(map[string]map[string]interface{})
+			}
+			for resName, config := range resourceMaps {
+				var configVal interface{} = config
+				if normalize {
+					configVal = normalizeConfigValue(config)
+				}
+				resources[resType][resName] = resolveConfigValue(configVal, resolve).(map[string]interface{})
+			}
+		}
+		// Modules write the same data blocks as the root module
+		for dataSourceType, dataSourceMaps := range module.DataSources {
+			if dataSources[dataSourceType] == nil {
+				dataSources[dataSourceType] = make(map[string]map[string]interface{})
+			}
+			for name, config := range dataSourceMaps {
+				dataSources[dataSourceType][name] = config
+			}
+		}
+	}
+
+	addResources(rootModule, resolveRoot)
+	for moduleName, childModule := range childModules {
+		// Module variables are set by the inputs in the root module
+		inputs := rootModule.Modules[moduleName]
+		addResources(childModule, func(val interface{}) interface{} {
+			if str, ok := val.(string); ok {
+				if matches := variableRefExpression.FindStringSubmatch(str); matches != nil {
+					if input, ok := inputs[matches[1]]; ok {
+						return resolveRoot(input)
+					}
+				}
+			}
+			return val
+		})
+	}
+
+	if len(resources) == 0 {
+		return nil, nil, diag.Errorf("No exported resources found in %s", directory)
+	}
+	return resources, dataSources, nil
+}
+
+func resolveConfigValue(val interface{}, resolve func(interface{}) interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = resolveConfigValue(elem, resolve)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = resolveConfigValue(elem, resolve)
+		}
+		return v
+	}
+	return resolve(val)
+}
+
+// Loads the JSON and HCL config files in a directory
+func loadExportedModule(directory string) (*exportedModule, diag.Diagnostics) {
+	module := &exportedModule{
+		Resources:   make(map[string]map[string]map[string]interface{}),
+		DataSources: make(map[string]map[string]map[string]interface{}),
+		Modules:     make(map[string]map[string]interface{}),
+		Outputs:     make(map[string]interface{}),
+	}
+
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, diag.Errorf("Failed to read export directory %s: %v", directory, err)
+	}
+	for _, file := range files {
+		path := filepath.Join(directory, file.Name())
+		var rootObject map[string]interface{}
+		var diagErr diag.Diagnostics
+		switch {
+		case file.IsDir():
+			continue
+		case strings.HasSuffix(file.Name(), ".tf.json"):
+			rootObject, diagErr = loadJSONConfigFile(path)
+		case strings.HasSuffix(file.Name(), ".tf"):
+			rootObject, diagErr = loadHCLConfigFile(path)
+		default:
+			continue
+		}
+		if diagErr != nil {
+			return nil, diagErr
+		}
+		module.addConfig(rootObject)
+	}
+	return module, nil
+}
+
+func (m *exportedModule) addConfig(rootObject map[string]interface{}) {
+	resourceTypes, _ := rootObject["resource"].(map[string]interface{})
+	for resType, resourceMaps := range resourceTypes {
+		if m.Resources[resType] == nil {
+			m.Resources[resType] = make(map[string]map[string]interface{})
+		}
+		resources, _ := resourceMaps.(map[string]interface{})
+		for resName, config := range resources {
+			if configMap, ok := config.(map[string]interface{}); ok {
+				m.Resources[resType][resName] = configMap
+			}
+		}
+	}
+	dataSourceTypes, _ := rootObject["data"].(map[string]interface{})
+	for dataSourceType, dataSourceMaps := range dataSourceTypes {
+		if m.DataSources[dataSourceType] == nil {
+			m.DataSources[dataSourceType] = make(map[string]map[string]interface{})
+		}
+		dataSources, _ := dataSourceMaps.(map[string]interface{})
+		for name, config := range dataSources {
+			if configMap, ok := config.(map[string]interface{}); ok {
+				m.DataSources[dataSourceType][name] = configMap
+			}
+		}
+	}
+	modules, _ := rootObject["module"].(map[string]interface{})
+	for moduleName, settings := range modules {
+		if settingsMap, ok := settings.(map[string]interface{}); ok {
+			m.Modules[moduleName] = settingsMap
+		}
+	}
+	outputs, _ := rootObject["output"].(map[string]interface{})
+	for name, output := range outputs {
+		m.Outputs[name] = output
+	}
+}
+
+// Loads the variable values from a tfvars file in the directory
+func loadExportedVariableValues(directory string) (map[string]interface{}, diag.Diagnostics) {
+	jsonPath := filepath.Join(directory, defaultTfVarsFile+".json")
+	if _, err := os.Stat(jsonPath); err == nil {
+		values, diagErr := loadJSONConfigFile(jsonPath)
+		if diagErr != nil {
+			return nil, diagErr
+		}
+		// Values are literals, so strings are escaped to compare them with the config
+		for name, val := range values {
+			values[name] = resolveConfigValue(val, func(val interface{}) interface{} {
+				if str, ok := val.(string); ok {
+					return escapeString(str)
+				}
+				return val
+			})
+		}
+		return values, nil
+	}
+	hclPath := filepath.Join(directory, defaultTfVarsFile)
+	if _, err := os.Stat(hclPath); err == nil {
+		return loadHCLConfigFile(hclPath)
+	}
+	return map[string]interface{}{}, nil
+}
+
+func loadJSONConfigFile(path string) (map[string]interface{}, diag.Diagnostics) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, diag.Errorf("Failed to read %s: %v", path, err)
+	}
+	var rootObject map[string]interface{}
+	if err := json.Unmarshal(data, &rootObject); err != nil {
+		return nil, diag.Errorf("Failed to parse %s: %v", path, err)
+	}
+	return rootObject, nil
+}
+
+// Loads an HCL config file into the same structure as a JSON config file. Nested blocks
+// are loaded as lists of objects, and references are loaded as ${} expression strings.
+func loadHCLConfigFile(path string) (map[string]interface{}, diag.Diagnostics) {
+	file, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, diag.Errorf("Failed to parse %s: %v", path, diags)
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, diag.Errorf("Failed to parse %s: unexpected body type", path)
+	}
+
+	rootObject, err := hclBodyToMap(body)
+	if err != nil {
+		return nil, diag.Errorf("Failed to read %s: %v", path, err)
+	}
+
+	// Labeled top-level blocks are keyed by their labels, e.g. resource.{type}.{name}
+	for _, block := range body.Blocks {
+		if len(block.Labels) == 0 {
+			continue
+		}
+		blockMap, err := hclBodyToMap(block.Body)
+		if err != nil {
+			return nil, diag.Errorf("Failed to read %s: %v", path, err)
+		}
+		parent, ok := rootObject[block.Type].(map[string]interface{})
+		if !ok {
+			parent = make(map[string]interface{})
+			rootObject[block.Type] = parent
+		}
+		for _, label := range block.Labels[:len(block.Labels)-1] {
+			child, ok := parent[label].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[label] = child
+			}
+			parent = child
+		}
+		parent[block.Labels[len(block.Labels)-1]] = blockMap
+	}
+	return rootObject, nil
+}
+
+func hclBodyToMap(body *hclsyntax.Body) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for name, attr := range body.Attributes {
+		val, err := hclExpressionToValue(attr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = val
+	}
+	for _, block := range body.Blocks {
+		if len(block.Labels) > 0 {
+			// Labeled blocks are only found at the top level of a config file
+			continue
+		}
+		blockMap, err := hclBodyToMap(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		blocks, _ := result[block.Type].([]interface{})
+		result[block.Type] = append(blocks, blockMap)
+	}
+	return result, nil
+}
+
+// Converts an expression in an exported HCL config to the value it would have in a JSON config
+func hclExpressionToValue(expr hclsyntax.Expression) (interface{}, error) {
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return "${" + traversalString(e.Traversal) + "}", nil
+	case *hclsyntax.TupleConsExpr:
+		result := make([]interface{}, 0, len(e.Exprs))
+		for _, elemExpr := range e.Exprs {
+			elem, err := hclExpressionToValue(elemExpr)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, elem)
+		}
+		return result, nil
+	case *hclsyntax.ObjectConsExpr:
+		result := make(map[string]interface{}, len(e.Items))
+		for _, item := range e.Items {
+			keyVal, diags := item.KeyExpr.Value(nil)
+			if diags.HasErrors() || keyVal.Type() != cty.String {
+				return nil, fmt.Errorf("invalid object key at %s", item.KeyExpr.Range())
+			}
+			elem, err := hclExpressionToValue(item.ValueExpr)
+			if err != nil {
+				return nil, err
+			}
+			result[keyVal.AsString()] = elem
+		}
+		return result, nil
+	}
+
+	val, diags := expr.Value(&hcl.EvalContext{Functions: exportedConfigFunctions})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to evaluate expression at %s: %v", expr.Range(), diags)
+	}
+	if val.IsNull() {
+		return nil, nil
+	}
+	if val.Type() == cty.String && val.IsKnown() {
+		// Strings in a JSON config are escaped template expressions
+		return escapeString(val.AsString()), nil
+	}
+	valJSON, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(valJSON, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func traversalString(traversal hcl.Traversal) string {
+	var sb strings.Builder
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			sb.WriteString(s.Name)
+		case hcl.TraverseAttr:
+			sb.WriteString("." + s.Name)
+		case hcl.TraverseIndex:
+			if s.Key.Type() == cty.String {
+				sb.WriteString(fmt.Sprintf("[%q]", s.Key.AsString()))
+			} else {
+				bf := s.Key.AsBigFloat()
+				sb.WriteString("[" + bf.Text('f', -1) + "]")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func driftReportMarkdown(report *driftReport) string {
+	var sb strings.Builder
+	sb.WriteString("# Drift Report\n\n")
+	sb.WriteString(fmt.Sprintf("Changes in the Genesys Cloud org since the export in `%s`.\n\n", report.Directory))
+	sb.WriteString("| Added | Removed | Modified |\n|---|---|---|\n")
+	sb.WriteString(fmt.Sprintf("| %d | %d | %d |\n", len(report.Added), len(report.Removed), len(report.Modified)))
+
+	writeResourceList := func(title string, resources []driftResource) {
+		if len(resources) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", title))
+		for _, resource := range resources {
+			sb.WriteString(fmt.Sprintf("- `%s.%s`\n", resource.Type, resource.Name))
+		}
+	}
+	writeResourceList("Added", report.Added)
+	writeResourceList("Removed", report.Removed)
+
+	if len(report.Modified) > 0 {
+		sb.WriteString("\n## Modified\n")
+		for _, resource := range report.Modified {
+			sb.WriteString(fmt.Sprintf("\n### `%s.%s`\n\n", resource.Type, resource.Name))
+			sb.WriteString("| Attribute | Exported | Current |\n|---|---|---|\n")
+			for _, attr := range resource.Attributes {
+				sb.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", attr.Path, markdownValue(attr.Exported), markdownValue(attr.Current)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func markdownValue(val interface{}) string {
+	if val == nil {
+		return ""
+	}
+	valJSON, err := json.Marshal(val)
+	if err != nil {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(string(valJSON), "|", "\\|") + "`"
+}
@@ -0,0 +1,98 @@
+package genesyscloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// Exports the files used by each resource with an ExportFilesFunc and updates their config to reference the exported files.
+// If a report is set, errors are recorded in the report and the other files are still exported.
+func exportResourceFiles(
+	resources []resourceInfo,
+	resourceTypeJSONMaps map[string]map[string]jsonMap,
+	exporters map[string]*ResourceExporter,
+	exportDir string,
+	meta interface{},
+	report *exportReport) diag.Diagnostics {
+
+	errorChan := make(chan diag.Diagnostics, len(resources))
+
+	// Cancel remaining goroutines if an error occurs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, resource := range resources {
+		exporter := exporters[resource.Type]
+		configMap := resourceTypeJSONMaps[resource.Type][resource.Name]
+		if exporter == nil || exporter.ExportFilesFunc == nil || configMap == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(resource resourceInfo, exportFiles ExportFilesFunc, configMap jsonMap) {
+			defer wg.Done()
+			if err := exportFiles(ctx, resource.State.ID, resource.Name, configMap, exportDir, meta); err != nil {
+				if report != nil {
+					report.addFileError(resource.Type, resource.State.ID, err)
+					return
+				}
+				errorChan <- err
+				cancel() // Stop other requests
+			}
+		}(resource, exporter.ExportFilesFunc, configMap)
+	}
+	wg.Wait()
+
+	// Return the first error if one was received
+	select {
+	case err := <-errorChan:
+		return err
+	default:
+		return nil
+	}
+}
+
+// Max time to download a file, so a stalled download does not hold up the export
+var exportFileTimeout = 5 * time.Minute
+
+// Downloads a file to a path in the export directory, creating its parent directories as needed
+func downloadExportFile(ctx context.Context, url string, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: exportFileTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status downloading %s: %s", url, response.Status)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	log.Printf("Downloading export file to %s", path)
+	_, err = io.Copy(file, response.Body)
+	return err
+}
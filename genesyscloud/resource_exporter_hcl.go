@@ -0,0 +1,321 @@
+package genesyscloud
+
+import (
+	"encoding/json"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// Matches the expressions generated for exported values, e.g. ${genesyscloud_user.my_user.id} or ${var.my_var}
+var hclExpression = regexp.MustCompile(`^\$\{([A-Za-z_][0-9A-Za-z_-]*(?:\.[A-Za-z_][0-9A-Za-z_-]*)+)\}$`)
+
+// Converts a root JSON config object into an HCL config file
+func writeHCLConfig(rootJSONObject jsonMap, exporters map[string]*ResourceExporter, provider *schema.Provider, path string) diag.Diagnostics {
+	hclFile := hclwrite.NewEmptyFile()
+	rootBody := hclFile.Body()
+
+	if terraformSettings, ok := rootJSONObject["terraform"].(jsonMap); ok {
+		terraformBody := rootBody.AppendNewBlock("terraform", nil).Body()
+		for _, blockType := range sortedKeys(terraformSettings) {
+			if settings, ok := terraformSettings[blockType].(jsonMap); ok {
+				addHCLAttributes(terraformBody.AppendNewBlock(blockType, nil).Body(), settings)
+			}
+		}
+	}
+
+	for _, blockType := range []string{"variable", "module"} {
+		blocks, _ := rootJSONObject[blockType].(jsonMap)
+		for _, name := range sortedKeys(blocks) {
+			rootBody.AppendNewline()
+			blockBody := rootBody.AppendNewBlock(blockType, []string{name}).Body()
+			addHCLAttributes(blockBody, blocks[name].(jsonMap))
+			if varType, ok := blocks[name].(jsonMap)["type"].(string); ok && blockType == "variable" {
+				// Type constraints are keywords rather than strings
+				blockBody.SetAttributeRaw("type", hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(varType)}})
+			}
+		}
+	}
+
+	if dataSourceMaps, ok := rootJSONObject["data"].(map[string]map[string]jsonMap); ok {
+		for _, dataSourceType := range sortedResourceTypes(dataSourceMaps) {
+			for _, name := range sortedJSONMapKeys(dataSourceMaps[dataSourceType]) {
+				rootBody.AppendNewline()
+				addHCLAttributes(rootBody.AppendNewBlock("data", []string{dataSourceType, name}).Body(), dataSourceMaps[dataSourceType][name])
+			}
+		}
+	}
+
+	if resourceTypeJSONMaps, ok := rootJSONObject["resource"].(map[string]map[string]jsonMap); ok {
+		for _, resType := range sortedResourceTypes(resourceTypeJSONMaps) {
+			resource := provider.ResourcesMap[resType]
+			if resource == nil {
+				return diag.Errorf("Resource type %s not defined", resType)
+			}
+			resourceMaps := resourceTypeJSONMaps[resType]
+			for _, resName := range sortedJSONMapKeys(resourceMaps) {
+				rootBody.AppendNewline()
+				block := rootBody.AppendNewBlock("resource", []string{resType, resName})
+				addHCLBody(block.Body(), resourceMaps[resName], resource.Schema, exporters[resType], "")
+			}
+		}
+	}
+
+	outputs, _ := rootJSONObject["output"].(jsonMap)
+	for _, name := range sortedKeys(outputs) {
+		rootBody.AppendNewline()
+		addHCLAttributes(rootBody.AppendNewBlock("output", []string{name}).Body(), outputs[name].(jsonMap))
+	}
+
+	importBlocks, _ := rootJSONObject["import"].([]interface{})
+	for _, importBlock := range importBlocks {
+		importSettings := importBlock.(jsonMap)
+		to, diags := hclsyntax.ParseTraversalAbs([]byte(importSettings["to"].(string)), path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return diag.Errorf("Invalid import address %s: %v", importSettings["to"], diags)
+		}
+		rootBody.AppendNewline()
+		blockBody := rootBody.AppendNewBlock("import", nil).Body()
+		blockBody.SetAttributeTraversal("to", to)
+		blockBody.SetAttributeRaw("id", hclValueTokens(importSettings["id"]))
+	}
+
+	log.Printf("Writing export config file to %s", path)
+	return writeToFile(hclwrite.Format(hclFile.Bytes()), path)
+}
+
+// Writes a file of literal attribute values, e.g. a tfvars file. Unlike config values, the values are not escaped
+// and are never written as references.
+func writeHCLValues(values map[string]interface{}, path string) diag.Diagnostics {
+	hclFile := hclwrite.NewEmptyFile()
+	for _, key := range sortedKeys(values) {
+		if values[key] == nil {
+			continue
+		}
+		valJSON, err := json.Marshal(values[key])
+		if err != nil {
+			return diag.Errorf("Failed to encode the value of %s: %v", key, err)
+		}
+		valType, err := ctyjson.ImpliedType(valJSON)
+		if err != nil {
+			return diag.Errorf("Failed to encode the value of %s: %v", key, err)
+		}
+		ctyVal, err := ctyjson.Unmarshal(valJSON, valType)
+		if err != nil {
+			return diag.Errorf("Failed to encode the value of %s: %v", key, err)
+		}
+		hclFile.Body().SetAttributeValue(key, ctyVal)
+	}
+
+	log.Printf("Writing export values file to %s", path)
+	return writeToFile(hclwrite.Format(hclFile.Bytes()), path)
+}
+
+func addHCLAttributes(body *hclwrite.Body, attributes map[string]interface{}) {
+	for _, key := range sortedKeys(attributes) {
+		if attributes[key] != nil {
+			body.SetAttributeRaw(key, hclValueTokens(attributes[key]))
+		}
+	}
+}
+
+// Writes the attributes of a sanitized config map to an HCL body followed by any nested blocks.
+// Nested resources are written as blocks unless their schema is configured to use attribute syntax.
+func addHCLBody(body *hclwrite.Body, configMap map[string]interface{}, schemaMap map[string]*schema.Schema, exporter *ResourceExporter, prevAttr string) {
+	var blockKeys []string
+	for _, key := range sortedKeys(configMap) {
+		val := configMap[key]
+		if val == nil {
+			// Null values are omitted from the config
+			continue
+		}
+
+		if nestedResource := getNestedBlockResource(schemaMap[key]); nestedResource != nil {
+			if _, ok := val.([]interface{}); ok {
+				blockKeys = append(blockKeys, key)
+				continue
+			}
+		}
+
+		currAttr := key
+		if prevAttr != "" {
+			currAttr = prevAttr + "." + key
+		}
+
+		if strVal, ok := val.(string); ok && exporter.isJsonEncodeAttribute(currAttr) {
+			if tokens := jsonEncodeTokens(strVal); tokens != nil {
+				body.SetAttributeRaw(key, tokens)
+				continue
+			}
+		}
+		body.SetAttributeRaw(key, hclValueTokens(val))
+	}
+
+	for _, key := range blockKeys {
+		currAttr := key
+		if prevAttr != "" {
+			currAttr = prevAttr + "." + key
+		}
+		nestedResource := getNestedBlockResource(schemaMap[key])
+		for _, blockVal := range configMap[key].([]interface{}) {
+			if blockMap, ok := blockVal.(map[string]interface{}); ok {
+				addHCLBody(body.AppendNewBlock(key, nil).Body(), blockMap, nestedResource.Schema, exporter, currAttr)
+			}
+		}
+	}
+}
+
+func getNestedBlockResource(s *schema.Schema) *schema.Resource {
+	if s == nil || s.ConfigMode == schema.SchemaConfigModeAttr {
+		return nil
+	}
+	if nestedResource, ok := s.Elem.(*schema.Resource); ok {
+		return nestedResource
+	}
+	return nil
+}
+
+func hclValueTokens(val interface{}) hclwrite.Tokens {
+	switch v := val.(type) {
+	case string:
+		if traversal := referenceTraversal(v); traversal != nil {
+			return hclwrite.TokensForTraversal(traversal)
+		}
+		// Strings have already been escaped for the JSON config syntax
+		return hclwrite.TokensForValue(cty.StringVal(unescapeString(v)))
+	case bool:
+		return hclwrite.TokensForValue(cty.BoolVal(v))
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(v)))
+	case float64:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(v))
+	case []interface{}:
+		tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+		multiline := false
+		for i, elem := range v {
+			if i > 0 {
+				tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+			}
+			if _, isMap := elem.(map[string]interface{}); isMap {
+				// Put each object on its own line for readability
+				multiline = true
+				tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+			}
+			tokens = append(tokens, hclValueTokens(elem)...)
+		}
+		if multiline {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+		}
+		return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+	case jsonMap:
+		return hclValueTokens(map[string]interface{}(v))
+	case map[string]interface{}:
+		tokens := hclwrite.Tokens{
+			{Type: hclsyntax.TokenOBrace, Bytes: []byte("{")},
+			{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+		}
+		// Keep null values since objects in attribute syntax must set every attribute
+		for _, key := range sortedKeys(v) {
+			tokens = append(tokens, hclObjectKeyTokens(key)...)
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenEqual, Bytes: []byte("=")})
+			tokens = append(tokens, hclValueTokens(v[key])...)
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+		}
+		return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")})
+	}
+	return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte("null")}}
+}
+
+func hclObjectKeyTokens(key string) hclwrite.Tokens {
+	if hclsyntax.ValidIdentifier(key) {
+		return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(key)}}
+	}
+	return hclwrite.TokensForValue(cty.StringVal(key))
+}
+
+// Returns the names of the traversal in an expression created for an exported value, e.g. [genesyscloud_user my_user id]
+// for ${genesyscloud_user.my_user.id}. Returns nil if the value is not an expression.
+func referenceNames(val string) []string {
+	matches := hclExpression.FindStringSubmatch(val)
+	if matches == nil {
+		return nil
+	}
+	return strings.Split(matches[1], ".")
+}
+
+// Converts an expression created for an exported value into an HCL traversal.
+// Returns nil if the value is not an expression.
+func referenceTraversal(val string) hcl.Traversal {
+	names := referenceNames(val)
+	if names == nil {
+		return nil
+	}
+	traversal := hcl.Traversal{hcl.TraverseRoot{Name: names[0]}}
+	for _, name := range names[1:] {
+		traversal = append(traversal, hcl.TraverseAttr{Name: name})
+	}
+	return traversal
+}
+
+// Renders a JSON string as a jsonencode() expression. Returns nil if the string does not contain a JSON object or array.
+func jsonEncodeTokens(jsonStr string) hclwrite.Tokens {
+	jsonBytes := []byte(unescapeString(jsonStr))
+	ctyType, err := ctyjson.ImpliedType(jsonBytes)
+	if err != nil || !(ctyType.IsObjectType() || ctyType.IsTupleType()) {
+		return nil
+	}
+	ctyVal, err := ctyjson.Unmarshal(jsonBytes, ctyType)
+	if err != nil {
+		return nil
+	}
+
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte("jsonencode")},
+		{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+	}
+	tokens = append(tokens, hclwrite.TokensForValue(ctyVal)...)
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte(")")})
+}
+
+// Reverses escapeString so values can be re-escaped by the HCL writer
+func unescapeString(strValue string) string {
+	unescapedVal := strings.ReplaceAll(strValue, "$${", "${")
+	unescapedVal = strings.ReplaceAll(unescapedVal, "%%{", "%{")
+	return unescapedVal
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedJSONMapKeys(m map[string]jsonMap) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedResourceTypes(m map[string]map[string]jsonMap) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,95 @@
+package genesyscloud
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// Terraform import blocks are written to a config file in the export directory. Requires Terraform 1.5 or later.
+	importModeBlocks = "import_blocks"
+
+	// A shell script of terraform import commands is written to the export directory
+	importModeScript = "import_script"
+
+	defaultImportsFile      = "imports"
+	defaultImportScriptFile = "import.sh"
+)
+
+type resourceImport struct {
+	Address string
+	ID      string
+}
+
+// Returns the address and import ID of each exported resource sorted by address
+func buildResourceImports(resources []resourceInfo, layout string) []resourceImport {
+	imports := make([]resourceImport, 0, len(resources))
+	for _, resource := range resources {
+		address := resource.Type + "." + resource.Name
+		if module := getResourceTypeModule(layout, resource.Type); module != "" {
+			address = module + "." + address
+		}
+		imports = append(imports, resourceImport{Address: address, ID: resource.ImportID})
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Address < imports[j].Address
+	})
+	return imports
+}
+
+func writeImports(
+	resources []resourceInfo,
+	directory string,
+	importMode string,
+	layout string,
+	exportAsHCL bool,
+	exporters map[string]*ResourceExporter,
+	provider *schema.Provider) diag.Diagnostics {
+
+	imports := buildResourceImports(resources, layout)
+	if importMode == importModeScript {
+		return writeImportScript(imports, filepath.Join(directory, defaultImportScriptFile))
+	}
+
+	importBlocks := make([]interface{}, 0, len(imports))
+	for _, imp := range imports {
+		importBlocks = append(importBlocks, jsonMap{
+			"to": imp.Address,
+			"id": imp.ID,
+		})
+	}
+	path := filepath.Join(directory, getConfigFileName(defaultImportsFile, exportAsHCL))
+	return writeConfigFile(jsonMap{"import": importBlocks}, path, exportAsHCL, exporters, provider)
+}
+
+func writeImportScript(imports []resourceImport, path string) diag.Diagnostics {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString("# Imports the exported resources into the state of the Terraform working directory\n")
+	script.WriteString("set -e\n\n")
+	for _, imp := range imports {
+		script.WriteString(fmt.Sprintf("terraform import %s %s\n", shellQuote(imp.Address), shellQuote(imp.ID)))
+	}
+
+	log.Printf("Writing export import script to %s", path)
+	return writeToFile([]byte(script.String()), path)
+}
+
+// Quotes a value as a single shell word
+func shellQuote(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", `'\''`) + "'"
+}
+
+// Removes the import files written for each import mode from the export directory
+func removeImportFiles(directory string) {
+	removeExportFile(filepath.Join(directory, defaultImportScriptFile))
+	for _, exportAsHCL := range []bool{false, true} {
+		removeExportFile(filepath.Join(directory, getConfigFileName(defaultImportsFile, exportAsHCL)))
+	}
+}
@@ -0,0 +1,181 @@
+package genesyscloud
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// Manifest of the versions of exported objects of types that expose a version instead of a modification date
+const defaultVersionsManifestFile = "versions.json"
+
+// exportVersionsManifest is a map of resource types to object IDs and the versions of the objects when they were exported
+type exportVersionsManifest map[string]map[string]int
+
+// Reads the versions manifest written by a previous export to the directory. A missing manifest is treated as empty.
+func readVersionsManifest(directory string) (exportVersionsManifest, diag.Diagnostics) {
+	manifest := make(exportVersionsManifest)
+	data, err := ioutil.ReadFile(filepath.Join(directory, defaultVersionsManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return nil, diag.Errorf("Failed to read versions manifest in %s: %v", directory, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, diag.Errorf("Failed to parse versions manifest in %s: %v", directory, err)
+	}
+	return manifest, nil
+}
+
+// Updates the manifest with the versions of the objects loaded for each exported type and writes it to the directory.
+// Like the names manifest, objects that were filtered from the export keep their versions until they no longer exist.
+func writeVersionsManifest(manifest exportVersionsManifest, exporters map[string]*ResourceExporter, directory string) diag.Diagnostics {
+	for resType, exporter := range exporters {
+		if exporter.SanitizedResourceMap == nil {
+			// Failed to load when continuing on errors
+			continue
+		}
+		versions := manifest[resType]
+		if versions == nil {
+			versions = make(map[string]int)
+		}
+		for id := range versions {
+			if !exporter.ListedIDs[id] {
+				delete(versions, id)
+			}
+		}
+		for id, meta := range exporter.SanitizedResourceMap {
+			if meta.Version != nil {
+				versions[id] = *meta.Version
+			} else {
+				delete(versions, id)
+			}
+		}
+		if len(versions) > 0 {
+			manifest[resType] = versions
+		} else {
+			delete(manifest, resType)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return diag.Errorf("Failed to encode versions manifest: %v", err)
+	}
+
+	path := filepath.Join(directory, defaultVersionsManifestFile)
+	log.Printf("Writing export versions manifest to %s", path)
+	return writeToFile(data, path)
+}
+
+// Marks the resources that have not been modified since a time, or whose version is the same as in the previous
+// export, and are already in an existing export so they are not read again. Only resources with a known modification
+// date or version can be skipped, and the number of resources read without one is recorded in the report if set.
+// Returns the skipped resources.
+func markUnmodifiedResources(
+	exporters map[string]*ResourceExporter,
+	existingResources map[string]map[string]map[string]interface{},
+	modifiedSince time.Time,
+	previousVersions exportVersionsManifest,
+	report *exportReport) []resourceInfo {
+
+	var unmodified []resourceInfo
+	for resType, exporter := range exporters {
+		exporter.UnmodifiedIDs = make(map[string]bool)
+		noModificationDate := 0
+		for id, meta := range exporter.SanitizedResourceMap {
+			if meta.DateModified != nil {
+				if !meta.DateModified.Before(modifiedSince) {
+					continue
+				}
+			} else if meta.Version != nil {
+				if version, ok := previousVersions[resType][id]; !ok || version != *meta.Version {
+					continue
+				}
+			} else {
+				noModificationDate++
+				continue
+			}
+			if existingResources[resType][meta.Name] == nil {
+				// Not in the existing export, e.g. a previous export filtered it out
+				continue
+			}
+			exporter.UnmodifiedIDs[id] = true
+			unmodified = append(unmodified, resourceInfo{
+				Name:     meta.Name,
+				Type:     resType,
+				ImportID: meta.IdPrefix + id,
+			})
+		}
+		log.Printf("Skipping %d unmodified resources for type %s", len(exporter.UnmodifiedIDs), resType)
+		if noModificationDate > 0 {
+			log.Printf("Reading %d resources for type %s without a modification date", noModificationDate, resType)
+			if report != nil {
+				report.setNoModificationDateCount(resType, noModificationDate)
+			}
+		}
+	}
+	return unmodified
+}
+
+// Merges the resources read for an incremental export into the existing export. Unmodified resources keep their
+// existing config, and existing resources of types that were not exported are kept unchanged. Resources of the
+// exported types that no longer exist are removed. Data blocks referenced by the existing config are added to dataSources.
+func mergeExportedResources(
+	resourceTypeJSONMaps map[string]map[string]jsonMap,
+	unmodified []resourceInfo,
+	existingResources map[string]map[string]map[string]interface{},
+	existingDataSources map[string]map[string]map[string]interface{},
+	exporters map[string]*ResourceExporter,
+	dataSources exportedDataSources) diag.Diagnostics {
+
+	existingConfigs := make(map[string]jsonMap)
+	addExisting := func(resType string, resName string) {
+		if resourceTypeJSONMaps[resType] == nil {
+			resourceTypeJSONMaps[resType] = make(map[string]jsonMap)
+		}
+		if resourceTypeJSONMaps[resType][resName] != nil {
+			return
+		}
+		config := jsonMap(existingResources[resType][resName])
+		resourceTypeJSONMaps[resType][resName] = config
+		existingConfigs[resType+"."+resName] = config
+	}
+
+	for _, resource := range unmodified {
+		addExisting(resource.Type, resource.Name)
+	}
+	for resType, resourceMaps := range existingResources {
+		if exporters[resType] != nil {
+			continue
+		}
+		for resName := range resourceMaps {
+			addExisting(resType, resName)
+		}
+	}
+
+	// Keep the data blocks referenced by the existing config
+	existingDataSourceMaps := make(map[string]map[string]jsonMap)
+	for dataSourceType, dataSourceMaps := range existingDataSources {
+		existingDataSourceMaps[dataSourceType] = make(map[string]jsonMap)
+		for name, config := range dataSourceMaps {
+			existingDataSourceMaps[dataSourceType][name] = config
+		}
+	}
+	for dataSourceType, dataSourceMaps := range getReferencedDataSources(existingConfigs, existingDataSourceMaps) {
+		for name, config := range dataSourceMaps {
+			objectName, ok := config["name"].(string)
+			if !ok {
+				return diag.Errorf("Data source %s.%s in the existing export has no name", dataSourceType, name)
+			}
+			dataSources.addExisting(dataSourceType, name, unescapeString(objectName))
+		}
+	}
+	return nil
+}
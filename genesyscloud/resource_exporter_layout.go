@@ -0,0 +1,253 @@
+package genesyscloud
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// All resources are exported into a single config file
+	layoutSingleFile = "single_file"
+
+	// Resources of each type are exported to a separate config file in the export directory
+	layoutFilePerType = "file_per_type"
+
+	// Resources of each type are exported to a module in a subdirectory of the export directory
+	layoutModulePerType = "module_per_type"
+
+	// Name of the config file written to each module directory
+	moduleConfigFileName = "main"
+)
+
+// Returns the type and name of the resource in a reference expression generated by resolveReference,
+// e.g. ${genesyscloud_user.my_user.id}. Returns false if the value is not a resource reference.
+func parseResourceReference(val string) (string, string, bool) {
+	names := referenceNames(val)
+	if len(names) != 3 || names[2] != "id" {
+		return "", "", false
+	}
+	switch names[0] {
+	case "data", "var", "module":
+		return "", "", false
+	}
+	return names[0], names[1], true
+}
+
+// Short name of a resource type used for its file or module, e.g. routing_queue for genesyscloud_routing_queue
+func getResourceTypeShortName(resType string) string {
+	return strings.TrimPrefix(resType, "genesyscloud_")
+}
+
+// Module address of a resource type in the exported config. This is empty unless types are exported as modules.
+func getResourceTypeModule(layout string, resType string) string {
+	if layout == layoutModulePerType {
+		return "module." + getResourceTypeShortName(resType)
+	}
+	return ""
+}
+
+func getConfigFileName(baseName string, exportAsHCL bool) string {
+	if exportAsHCL {
+		return baseName + ".tf"
+	}
+	return baseName + ".tf.json"
+}
+
+func buildTerraformSettings(providerSource string, version string) jsonMap {
+	providerSettings := jsonMap{
+		"source": providerSource,
+	}
+	if version != "" {
+		providerSettings["version"] = version
+	}
+	return jsonMap{
+		"required_providers": jsonMap{
+			"genesyscloud": providerSettings,
+		},
+	}
+}
+
+func writeConfigFile(rootJSONObject jsonMap, path string, exportAsHCL bool, exporters map[string]*ResourceExporter, provider *schema.Provider) diag.Diagnostics {
+	if exportAsHCL {
+		return writeHCLConfig(rootJSONObject, exporters, provider, path)
+	}
+	return writeConfig(rootJSONObject, path)
+}
+
+// Writes the terraform settings to the root config file and the resources of each type to their own config file in the same directory
+func writeConfigFilePerType(
+	resourceTypeJSONMaps map[string]map[string]jsonMap,
+	rootPath string,
+	exportAsHCL bool,
+	exporters map[string]*ResourceExporter,
+	provider *schema.Provider,
+	providerSource string,
+	version string,
+	dataSources exportedDataSources) diag.Diagnostics {
+
+	// Data sources are written to the root config file with the terraform settings
+	rootJSONObject := jsonMap{"terraform": buildTerraformSettings(providerSource, version)}
+	if len(dataSources) > 0 {
+		rootJSONObject["data"] = dataSources.jsonMaps()
+	}
+	if err := writeConfigFile(rootJSONObject, rootPath, exportAsHCL, exporters, provider); err != nil {
+		return err
+	}
+
+	directory := filepath.Dir(rootPath)
+	for resType, resourceMaps := range resourceTypeJSONMaps {
+		typeJSONObject := jsonMap{
+			"resource": map[string]map[string]jsonMap{resType: resourceMaps},
+		}
+		typePath := filepath.Join(directory, getConfigFileName(getResourceTypeShortName(resType), exportAsHCL))
+		if err := writeConfigFile(typeJSONObject, typePath, exportAsHCL, exporters, provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes each resource type to a module in its own subdirectory. References between types are passed through
+// module outputs and variables, and the root config file declares each module.
+func writeConfigModulePerType(
+	resourceTypeJSONMaps map[string]map[string]jsonMap,
+	rootPath string,
+	exportAsHCL bool,
+	exporters map[string]*ResourceExporter,
+	provider *schema.Provider,
+	providerSource string,
+	version string,
+	variables []*exportVariable,
+	dataSources exportedDataSources) diag.Diagnostics {
+
+	// Module name -> variable name -> output reference from another module or a root variable
+	moduleInputs := make(map[string]jsonMap)
+	// Module name -> variable name -> variable declaration
+	moduleVariables := make(map[string]jsonMap)
+	// Module name -> output name -> output settings
+	moduleOutputs := make(map[string]jsonMap)
+	for resType := range resourceTypeJSONMaps {
+		moduleName := getResourceTypeShortName(resType)
+		moduleInputs[moduleName] = jsonMap{}
+		moduleVariables[moduleName] = jsonMap{}
+		moduleOutputs[moduleName] = jsonMap{}
+	}
+
+	// Extracted variables are declared in the root module and passed through to the module of their resource
+	for _, variable := range variables {
+		moduleName := getResourceTypeShortName(variable.ResourceType)
+		moduleInputs[moduleName][variable.Name] = fmt.Sprintf("${var.%s}", variable.Name)
+		moduleVariables[moduleName][variable.Name] = variable.Declaration
+	}
+
+	for resType, resourceMaps := range resourceTypeJSONMaps {
+		moduleName := getResourceTypeShortName(resType)
+		for _, config := range resourceMaps {
+			replaceModuleReferences(config, func(refType string, refName string) string {
+				if refType == resType || resourceTypeJSONMaps[refType] == nil {
+					// References within the same module are unchanged
+					return ""
+				}
+				refModule := getResourceTypeShortName(refType)
+				outputName := refName + "_id"
+				moduleOutputs[refModule][outputName] = jsonMap{
+					"value": fmt.Sprintf("${%s.%s.id}", refType, refName),
+				}
+				varName := refModule + "_" + outputName
+				moduleInputs[moduleName][varName] = fmt.Sprintf("${module.%s.%s}", refModule, outputName)
+				moduleVariables[moduleName][varName] = jsonMap{"type": "string"}
+				return fmt.Sprintf("${var.%s}", varName)
+			})
+		}
+	}
+
+	dataSourceMaps := dataSources.jsonMaps()
+	rootModules := jsonMap{}
+	directory := filepath.Dir(rootPath)
+	for resType, resourceMaps := range resourceTypeJSONMaps {
+		moduleName := getResourceTypeShortName(resType)
+		moduleDir := filepath.Join(directory, moduleName)
+		if err := os.MkdirAll(moduleDir, os.ModePerm); err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Modules must declare the provider source, but the version is only constrained by the root module
+		moduleJSONObject := jsonMap{
+			"terraform": buildTerraformSettings(providerSource, ""),
+			"resource":  map[string]map[string]jsonMap{resType: resourceMaps},
+		}
+		if len(moduleVariables[moduleName]) > 0 {
+			moduleJSONObject["variable"] = moduleVariables[moduleName]
+		}
+		if len(moduleOutputs[moduleName]) > 0 {
+			moduleJSONObject["output"] = moduleOutputs[moduleName]
+		}
+		// Each module looks up the data sources it references
+		if moduleDataSources := getReferencedDataSources(resourceMaps, dataSourceMaps); len(moduleDataSources) > 0 {
+			moduleJSONObject["data"] = moduleDataSources
+		}
+
+		modulePath := filepath.Join(moduleDir, getConfigFileName(moduleConfigFileName, exportAsHCL))
+		if err := writeConfigFile(moduleJSONObject, modulePath, exportAsHCL, exporters, provider); err != nil {
+			return err
+		}
+
+		moduleSettings := jsonMap{"source": "./" + moduleName}
+		for varName, value := range moduleInputs[moduleName] {
+			moduleSettings[varName] = value
+		}
+		rootModules[moduleName] = moduleSettings
+	}
+
+	rootJSONObject := jsonMap{
+		"terraform": buildTerraformSettings(providerSource, version),
+		"module":    rootModules,
+	}
+	return writeConfigFile(rootJSONObject, rootPath, exportAsHCL, exporters, provider)
+}
+
+// Replaces resource reference expressions in a config map with the result of the replace function.
+// References are left unchanged if the function returns an empty string.
+func replaceModuleReferences(configMap map[string]interface{}, replace func(refType string, refName string) string) {
+	for key, val := range configMap {
+		configMap[key] = replaceModuleReferencesInValue(val, replace)
+	}
+}
+
+func replaceModuleReferencesInValue(val interface{}, replace func(refType string, refName string) string) interface{} {
+	switch v := val.(type) {
+	case string:
+		if refType, refName, ok := parseResourceReference(v); ok {
+			if replacement := replace(refType, refName); replacement != "" {
+				return replacement
+			}
+		}
+	case jsonMap:
+		replaceModuleReferences(v, replace)
+	case map[string]interface{}:
+		replaceModuleReferences(v, replace)
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = replaceModuleReferencesInValue(elem, replace)
+		}
+	}
+	return val
+}
+
+// Removes the files written for each layout from the export directory
+func removeLayoutFiles(directory string) {
+	for _, resType := range getAvailableExporterTypes() {
+		shortName := getResourceTypeShortName(resType)
+		for _, exportAsHCL := range []bool{false, true} {
+			removeExportFile(filepath.Join(directory, getConfigFileName(shortName, exportAsHCL)))
+			removeExportFile(filepath.Join(directory, shortName, getConfigFileName(moduleConfigFileName, exportAsHCL)))
+		}
+		// Only removed if nothing else was added to the module directory
+		os.Remove(filepath.Join(directory, shortName))
+	}
+}
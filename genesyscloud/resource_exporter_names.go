@@ -0,0 +1,152 @@
+package genesyscloud
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Manifest of the names given to exported objects, reused by later exports so objects keep their Terraform address
+const defaultNamesManifestFile = "names.json"
+
+// exportNamesManifest is a map of resource types to object IDs and the names of their exported resources
+type exportNamesManifest map[string]map[string]string
+
+// Reads the names manifest written by a previous export to the directory. A missing manifest is treated as empty.
+func readNamesManifest(directory string) (exportNamesManifest, diag.Diagnostics) {
+	manifest := make(exportNamesManifest)
+	data, err := ioutil.ReadFile(filepath.Join(directory, defaultNamesManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return nil, diag.Errorf("Failed to read names manifest in %s: %v", directory, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, diag.Errorf("Failed to parse names manifest in %s: %v", directory, err)
+	}
+	return manifest, nil
+}
+
+// Updates the manifest with the names of the objects loaded for each exported type and writes it to the directory.
+// Objects that were filtered from the export keep their names, and only objects that no longer exist are removed.
+// Types that were not loaded, or failed to load, keep the names from the previous manifest.
+func writeNamesManifest(manifest exportNamesManifest, exporters map[string]*ResourceExporter, directory string) diag.Diagnostics {
+	for resType, exporter := range exporters {
+		if exporter.SanitizedResourceMap == nil {
+			// Failed to load when continuing on errors
+			continue
+		}
+		names := manifest[resType]
+		if names == nil {
+			names = make(map[string]string, len(exporter.SanitizedResourceMap))
+		}
+		for id := range names {
+			if !exporter.ListedIDs[id] {
+				delete(names, id)
+			}
+		}
+		for id, meta := range exporter.SanitizedResourceMap {
+			names[id] = meta.Name
+		}
+		manifest[resType] = names
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return diag.Errorf("Failed to encode names manifest: %v", err)
+	}
+
+	path := filepath.Join(directory, defaultNamesManifestFile)
+	log.Printf("Writing export names manifest to %s", path)
+	return writeToFile(data, path)
+}
+
+// Sets the names of objects found in a previous export and makes the remaining names unique.
+// Objects are processed in ID order and duplicates get a suffix based on their ID, so names are the same across exports.
+func applyResourceNames(idMetaMap ResourceIDMetaMap, previousNames map[string]string) {
+	ids := sortedResourceIDs(idMetaMap)
+	usedNames := make(map[string]bool, len(ids))
+	named := make(map[string]bool)
+
+	// Names of objects filtered from this export are kept for them
+	for id, name := range previousNames {
+		if idMetaMap[id] == nil {
+			usedNames[name] = true
+		}
+	}
+
+	// Previously exported names take precedence over new objects
+	for _, id := range ids {
+		if name := previousNames[id]; name != "" && !usedNames[name] {
+			idMetaMap[id].Name = name
+			usedNames[name] = true
+			named[id] = true
+		}
+	}
+	for _, id := range ids {
+		if named[id] {
+			continue
+		}
+		meta := idMetaMap[id]
+		if usedNames[meta.Name] {
+			meta.Name = meta.Name + "_" + getIDNameSuffix(id)
+		}
+		usedNames[meta.Name] = true
+	}
+}
+
+// Returns a suffix for the name of an object that is unique to the object and the same across exports
+func getIDNameSuffix(id string) string {
+	algorithm := fnv.New32()
+	algorithm.Write([]byte(id))
+	return strconv.FormatUint(uint64(algorithm.Sum32()), 10)
+}
+
+// Sorts the elements of set attributes by their JSON encoding, as the order read from the state is based on hashes
+func sortSetElements(configMap map[string]interface{}, schemaMap map[string]*schema.Schema) {
+	for key, val := range configMap {
+		attrSchema := schemaMap[key]
+		if attrSchema == nil {
+			continue
+		}
+		elems, ok := val.([]interface{})
+		if !ok {
+			continue
+		}
+		if elemResource, ok := attrSchema.Elem.(*schema.Resource); ok {
+			for _, elem := range elems {
+				if elemMap, ok := elem.(map[string]interface{}); ok {
+					sortSetElements(elemMap, elemResource.Schema)
+				}
+			}
+		}
+		if attrSchema.Type != schema.TypeSet {
+			continue
+		}
+
+		type sortElem struct {
+			key string
+			val interface{}
+		}
+		sorted := make([]sortElem, len(elems))
+		for i, elem := range elems {
+			encoded, _ := json.Marshal(elem)
+			sorted[i] = sortElem{key: string(encoded), val: elem}
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].key < sorted[j].key
+		})
+		for i := range sorted {
+			elems[i] = sorted[i].val
+		}
+	}
+}
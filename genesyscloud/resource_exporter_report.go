@@ -0,0 +1,162 @@
+package genesyscloud
+
+import (
+	"encoding/json"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const defaultExportReportFile = "export_report.json"
+
+// exportReport records the resources that could not be exported when the export continues on errors
+type exportReport struct {
+	mutex sync.Mutex
+
+	// Map of resource types to their results
+	ResourceTypes map[string]*exportTypeReport `json:"resource_types"`
+}
+
+type exportTypeReport struct {
+	// Number of objects found in the org after filtering
+	Found int `json:"found"`
+
+	// Number of objects exported
+	Exported int `json:"exported"`
+
+	// Number of objects skipped due to errors
+	Skipped int `json:"skipped"`
+
+	// Error loading the objects of the type. No objects of the type are exported when this is set.
+	Error string `json:"error,omitempty"`
+
+	// Map of the IDs of skipped objects to the error reading them
+	Errors map[string]string `json:"errors,omitempty"`
+
+	// Map of the IDs of exported objects to the error exporting their files. Their config is exported without the files.
+	FileErrors map[string]string `json:"file_errors,omitempty"`
+
+	// Number of objects read by an incremental export because the API does not expose their modification date.
+	// They are read whether or not they were modified.
+	NoModificationDate int `json:"no_modification_date,omitempty"`
+}
+
+func newExportReport() *exportReport {
+	return &exportReport{ResourceTypes: make(map[string]*exportTypeReport)}
+}
+
+func (r *exportReport) getTypeReport(resType string) *exportTypeReport {
+	if r.ResourceTypes[resType] == nil {
+		r.ResourceTypes[resType] = &exportTypeReport{}
+	}
+	return r.ResourceTypes[resType]
+}
+
+// Records an error loading the objects of a type
+func (r *exportReport) addTypeError(resType string, err diag.Diagnostics) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	log.Printf("Skipping resource type %s: %s", resType, diagnosticsString(err))
+	r.getTypeReport(resType).Error = diagnosticsString(err)
+}
+
+// Records an error reading an object that will be skipped
+func (r *exportReport) addResourceError(resType string, id string, err diag.Diagnostics) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	log.Printf("Skipping %s %s: %s", resType, id, diagnosticsString(err))
+	typeReport := r.getTypeReport(resType)
+	if typeReport.Errors == nil {
+		typeReport.Errors = make(map[string]string)
+	}
+	typeReport.Errors[id] = diagnosticsString(err)
+	typeReport.Skipped = len(typeReport.Errors)
+}
+
+// Records an error exporting the files of an object that is exported without them
+func (r *exportReport) addFileError(resType string, id string, err diag.Diagnostics) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	log.Printf("Failed to export files of %s %s: %s", resType, id, diagnosticsString(err))
+	typeReport := r.getTypeReport(resType)
+	if typeReport.FileErrors == nil {
+		typeReport.FileErrors = make(map[string]string)
+	}
+	typeReport.FileErrors[id] = diagnosticsString(err)
+}
+
+// Records the number of objects of a type that an incremental export reads because their modification date is unknown
+func (r *exportReport) setNoModificationDateCount(resType string, count int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.getTypeReport(resType).NoModificationDate = count
+}
+
+// Returns the number of exported objects whose files could not be exported
+func (r *exportReport) fileErrorCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	count := 0
+	for _, typeReport := range r.ResourceTypes {
+		count += len(typeReport.FileErrors)
+	}
+	return count
+}
+
+// Returns the total number of skipped objects and types that could not be loaded
+func (r *exportReport) skippedCounts() (int, int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	skippedResources, skippedTypes := 0, 0
+	for _, typeReport := range r.ResourceTypes {
+		skippedResources += typeReport.Skipped
+		if typeReport.Error != "" {
+			skippedTypes++
+		}
+	}
+	return skippedResources, skippedTypes
+}
+
+// Sets the found and exported counts of each type and writes the report to the directory
+func (r *exportReport) write(exporters map[string]*ResourceExporter, found map[string]int, resources []resourceInfo, directory string) diag.Diagnostics {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for resType := range exporters {
+		r.getTypeReport(resType).Found = found[resType]
+	}
+	for _, resource := range resources {
+		r.getTypeReport(resource.Type).Exported++
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return diag.Errorf("Failed to encode export report: %v", err)
+	}
+
+	path := filepath.Join(directory, defaultExportReportFile)
+	log.Printf("Writing export report to %s", path)
+	return writeToFile(data, path)
+}
+
+// Returns the summary and detail of each diagnostic on its own line
+func diagnosticsString(diags diag.Diagnostics) string {
+	var lines []string
+	for _, d := range diags {
+		line := d.Summary
+		if d.Detail != "" {
+			line += ": " + d.Detail
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
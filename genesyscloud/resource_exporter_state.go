@@ -0,0 +1,114 @@
+package genesyscloud
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/google/uuid"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	tfStateVersion = 4
+	// Provider source addresses in state require Terraform 0.13 or later
+	tfStateTerraformVersion = "0.13.0"
+)
+
+// tfStateV4 is the Terraform state file format used by Terraform 0.13 and later
+type tfStateV4 struct {
+	Version          int                    `json:"version"`
+	TerraformVersion string                 `json:"terraform_version"`
+	Serial           int64                  `json:"serial"`
+	Lineage          string                 `json:"lineage"`
+	Outputs          map[string]interface{} `json:"outputs"`
+	Resources        []tfStateResourceV4    `json:"resources"`
+}
+
+type tfStateResourceV4 struct {
+	Module    string              `json:"module,omitempty"`
+	Mode      string              `json:"mode"`
+	Type      string              `json:"type"`
+	Name      string              `json:"name"`
+	Provider  string              `json:"provider"`
+	Instances []tfStateInstanceV4 `json:"instances"`
+}
+
+type tfStateInstanceV4 struct {
+	SchemaVersion       int             `json:"schema_version"`
+	Attributes          json.RawMessage `json:"attributes"`
+	SensitiveAttributes []interface{}   `json:"sensitive_attributes"`
+}
+
+func buildTfStateV4(resources []resourceInfo, provider *schema.Provider, providerSource string, layout string, lineage string, serial int64) (*tfStateV4, diag.Diagnostics) {
+	tfstate := &tfStateV4{
+		Version:          tfStateVersion,
+		TerraformVersion: tfStateTerraformVersion,
+		Serial:           serial,
+		Lineage:          lineage,
+		Outputs:          map[string]interface{}{},
+		Resources:        []tfStateResourceV4{},
+	}
+
+	for _, resource := range resources {
+		stateVal, err := schema.StateValueFromInstanceState(resource.State, resource.CtyType)
+		if err != nil {
+			return nil, diag.Errorf("Failed to read state for %s.%s: %v", resource.Type, resource.Name, err)
+		}
+		attributes, err := ctyjson.Marshal(stateVal, resource.CtyType)
+		if err != nil {
+			return nil, diag.Errorf("Failed to encode state for %s.%s: %v", resource.Type, resource.Name, err)
+		}
+
+		schemaVersion := 0
+		if resourceSchema := provider.ResourcesMap[resource.Type]; resourceSchema != nil {
+			schemaVersion = resourceSchema.SchemaVersion
+		}
+
+		tfstate.Resources = append(tfstate.Resources, tfStateResourceV4{
+			Module:   getResourceTypeModule(layout, resource.Type),
+			Mode:     "managed",
+			Type:     resource.Type,
+			Name:     resource.Name,
+			Provider: `provider["` + providerSource + `"]`,
+			Instances: []tfStateInstanceV4{
+				{
+					SchemaVersion:       schemaVersion,
+					Attributes:          attributes,
+					SensitiveAttributes: []interface{}{},
+				},
+			},
+		})
+	}
+
+	// Sort by address so the state file is consistent between exports
+	sort.Slice(tfstate.Resources, func(i, j int) bool {
+		if tfstate.Resources[i].Type != tfstate.Resources[j].Type {
+			return tfstate.Resources[i].Type < tfstate.Resources[j].Type
+		}
+		return tfstate.Resources[i].Name < tfstate.Resources[j].Name
+	})
+	return tfstate, nil
+}
+
+// Reuses the lineage of an existing state file at the path so that re-exports are treated as
+// newer snapshots of the same state. A new lineage is generated if there is no readable state file.
+func getStateLineageAndSerial(path string) (string, int64) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Failed to read existing state file %s: %v", path, err)
+		}
+		return uuid.NewString(), 1
+	}
+
+	var existing tfStateV4
+	if err := json.Unmarshal(data, &existing); err != nil || existing.Version != tfStateVersion || existing.Lineage == "" {
+		return uuid.NewString(), 1
+	}
+	return existing.Lineage, existing.Serial + 1
+}
@@ -0,0 +1,159 @@
+package genesyscloud
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultVariablesFile = "variables"
+	defaultTfVarsFile    = "terraform.tfvars"
+)
+
+// exportVariable is a variable extracted from the config of an exported resource
+type exportVariable struct {
+	Name         string
+	ResourceType string
+	Declaration  jsonMap
+	Value        interface{}
+	Sensitive    bool
+}
+
+// Replaces the variable attributes of each exported resource with a variable reference and returns the variables sorted by name
+func extractVariables(
+	resourceTypeJSONMaps map[string]map[string]jsonMap,
+	exporters map[string]*ResourceExporter,
+	provider *schema.Provider) []*exportVariable {
+
+	var variables []*exportVariable
+	for _, resType := range sortedResourceTypes(resourceTypeJSONMaps) {
+		exporter := exporters[resType]
+		resource := provider.ResourcesMap[resType]
+		if exporter == nil || len(exporter.VariableAttrs) == 0 || resource == nil {
+			continue
+		}
+
+		resourceMaps := resourceTypeJSONMaps[resType]
+		for _, resName := range sortedJSONMapKeys(resourceMaps) {
+			config := resourceMaps[resName]
+			for attr, settings := range exporter.VariableAttrs {
+				if config[attr] == nil {
+					continue
+				}
+				attrSchema := resource.Schema[attr]
+				variable := &exportVariable{
+					Name:         fmt.Sprintf("%s_%s_%s", getResourceTypeShortName(resType), resName, attr),
+					ResourceType: resType,
+					Value:        rawVariableValue(config[attr]),
+					Sensitive:    settings.Sensitive || (attrSchema != nil && attrSchema.Sensitive),
+				}
+				variable.Declaration = jsonMap{
+					"type":        getVariableType(attrSchema),
+					"description": fmt.Sprintf("%s of %s.%s", attr, resType, resName),
+				}
+				if variable.Sensitive {
+					variable.Declaration["sensitive"] = true
+				}
+				config[attr] = fmt.Sprintf("${var.%s}", variable.Name)
+				variables = append(variables, variable)
+			}
+		}
+	}
+
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables
+}
+
+// Returns a copy of a sanitized config value with the strings as they were before escapeString.
+// Variable values are literals, so template sequences in them must not be escaped.
+func rawVariableValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return unescapeString(v)
+	case jsonMap:
+		return rawVariableValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			result[key] = rawVariableValue(elem)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = rawVariableValue(elem)
+		}
+		return result
+	}
+	return val
+}
+
+// Returns the Terraform type constraint for a variable set to an attribute
+func getVariableType(s *schema.Schema) string {
+	if s == nil {
+		return "any"
+	}
+	switch s.Type {
+	case schema.TypeString:
+		return "string"
+	case schema.TypeInt, schema.TypeFloat:
+		return "number"
+	case schema.TypeBool:
+		return "bool"
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		elemType := "any"
+		if elemSchema, ok := s.Elem.(*schema.Schema); ok {
+			elemType = getVariableType(elemSchema)
+		}
+		if s.Type == schema.TypeMap {
+			return fmt.Sprintf("map(%s)", elemType)
+		}
+		return fmt.Sprintf("list(%s)", elemType)
+	}
+	return "any"
+}
+
+// Writes the variable declarations and a tfvars file with the current value of each variable.
+// Values of sensitive variables are only written if includeSensitive is set.
+func writeVariables(
+	variables []*exportVariable,
+	directory string,
+	exportAsHCL bool,
+	includeSensitive bool,
+	exporters map[string]*ResourceExporter,
+	provider *schema.Provider) diag.Diagnostics {
+
+	declarations := jsonMap{}
+	values := jsonMap{}
+	for _, variable := range variables {
+		declarations[variable.Name] = variable.Declaration
+		if !variable.Sensitive || includeSensitive {
+			values[variable.Name] = variable.Value
+		}
+	}
+
+	variablesPath := filepath.Join(directory, getConfigFileName(defaultVariablesFile, exportAsHCL))
+	if err := writeConfigFile(jsonMap{"variable": declarations}, variablesPath, exportAsHCL, exporters, provider); err != nil {
+		return err
+	}
+
+	if exportAsHCL {
+		return writeHCLValues(values, filepath.Join(directory, defaultTfVarsFile))
+	}
+	return writeConfig(values, filepath.Join(directory, defaultTfVarsFile+".json"))
+}
+
+// Removes the variable files written for each config format from the export directory
+func removeVariableFiles(directory string) {
+	for _, exportAsHCL := range []bool{false, true} {
+		removeExportFile(filepath.Join(directory, getConfigFileName(defaultVariablesFile, exportAsHCL)))
+	}
+	removeExportFile(filepath.Join(directory, defaultTfVarsFile))
+	removeExportFile(filepath.Join(directory, defaultTfVarsFile+".json"))
+}
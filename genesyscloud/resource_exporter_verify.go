@@ -0,0 +1,217 @@
+package genesyscloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const (
+	// Resources that would change are written to the verify report and returned as a warning
+	verifyModeReport = "report"
+
+	// Resources that would change are written to the verify report and fail the export
+	verifyModeFail = "fail"
+
+	defaultVerifyReportFile = "verify_report.json"
+)
+
+type verifyReport struct {
+	Directory string `json:"directory"`
+
+	// Number of resources that would not change if the exported config was applied
+	Verified int `json:"verified"`
+
+	// Resources that would change or have invalid config
+	Failed []*verifyResource `json:"failed,omitempty"`
+
+	// Resources that could not be verified, e.g. because they reference objects that were not read
+	Unverified []*verifyResource `json:"unverified,omitempty"`
+}
+
+type verifyResource struct {
+	Address string                  `json:"address"`
+	ID      string                  `json:"id"`
+	Errors  []string                `json:"errors,omitempty"`
+	Changes []verifyAttributeChange `json:"changes,omitempty"`
+}
+
+type verifyAttributeChange struct {
+	Attribute   string `json:"attribute"`
+	State       string `json:"state"`
+	Config      string `json:"config"`
+	RequiresNew bool   `json:"requires_new,omitempty"`
+}
+
+// Loads the config written to the directory and computes the plan of each exported resource against the state
+// read from the org. Resources whose plan is not empty or whose config is invalid are written to the verify report.
+func verifyExport(
+	ctx context.Context,
+	resources []resourceInfo,
+	variables []*exportVariable,
+	dataSources exportedDataSources,
+	exporters map[string]*ResourceExporter,
+	provider *schema.Provider,
+	meta interface{},
+	directory string) (*verifyReport, diag.Diagnostics) {
+
+	exported, _, diagErr := loadExportedResources(directory, false)
+	if diagErr != nil {
+		return nil, diagErr
+	}
+
+	// IDs of the objects referenced by the config
+	resourceIDs := make(map[string]string, len(resources))
+	for _, resource := range resources {
+		if resource.State != nil {
+			resourceIDs[resource.Type+"."+resource.Name] = resource.State.ID
+		}
+	}
+	dataSourceIDs := make(map[string]string)
+	for dataSourceType, idMetaMap := range dataSources {
+		for id, meta := range idMetaMap {
+			dataSourceIDs[dataSourceType+"."+meta.Name] = id
+		}
+	}
+	variableValues := make(map[string]interface{}, len(variables))
+	for _, variable := range variables {
+		variableValues[variable.Name] = variable.Value
+	}
+
+	report := &verifyReport{Directory: directory}
+	for _, resource := range resources {
+		if resource.State == nil {
+			// Kept unchanged from an existing export
+			continue
+		}
+		address := resource.Type + "." + resource.Name
+		result := &verifyResource{Address: address, ID: resource.State.ID}
+
+		config := exported[resource.Type][resource.Name]
+		if config == nil {
+			result.Errors = append(result.Errors, "Resource not found in the exported config")
+			report.Failed = append(report.Failed, result)
+			continue
+		}
+
+		var unresolved []string
+		resolved := resolveConfigValue(config, func(val interface{}) interface{} {
+			str, ok := val.(string)
+			if !ok {
+				return val
+			}
+			if refType, refName, ok := parseResourceReference(str); ok {
+				if id, ok := resourceIDs[refType+"."+refName]; ok {
+					return id
+				}
+				unresolved = append(unresolved, str)
+			} else if matches := dataSourceRefExpression.FindStringSubmatch(str); matches != nil {
+				if id, ok := dataSourceIDs[matches[1]+"."+matches[2]]; ok && !strings.HasPrefix(id, existingDataSourceKeyPrefix) {
+					return id
+				}
+				unresolved = append(unresolved, str)
+			} else if matches := variableRefExpression.FindStringSubmatch(str); matches != nil {
+				if value, ok := variableValues[matches[1]]; ok {
+					return value
+				}
+				unresolved = append(unresolved, str)
+			}
+			return unescapeString(str)
+		}).(map[string]interface{})
+		if exporter := exporters[resource.Type]; exporter != nil && exporter.RestoreFilesFunc != nil {
+			stateConfig, diagErr := instanceStateToJSONMap(resource.State, resource.CtyType)
+			if diagErr != nil {
+				return nil, diagErr
+			}
+			exporter.RestoreFilesFunc(resolved, stateConfig)
+		}
+		if len(unresolved) > 0 {
+			sort.Strings(unresolved)
+			for _, ref := range unresolved {
+				result.Errors = append(result.Errors, fmt.Sprintf("Unable to resolve %s", ref))
+			}
+			report.Unverified = append(report.Unverified, result)
+			continue
+		}
+
+		resourceSchema := provider.ResourcesMap[resource.Type]
+		resourceConfig := terraform.NewResourceConfigRaw(resolved)
+		for _, d := range resourceSchema.Validate(resourceConfig) {
+			if d.Severity == diag.Error {
+				result.Errors = append(result.Errors, diagnosticsString(diag.Diagnostics{d}))
+			}
+		}
+		if len(result.Errors) == 0 {
+			instanceDiff, err := resourceSchema.SimpleDiff(ctx, resource.State, resourceConfig, meta)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to plan: %v", err))
+			} else {
+				result.Changes = getVerifyChanges(instanceDiff)
+			}
+		}
+
+		if len(result.Errors) > 0 || len(result.Changes) > 0 {
+			log.Printf("Exported config for %s is not a no-op", address)
+			report.Failed = append(report.Failed, result)
+		} else {
+			report.Verified++
+		}
+	}
+
+	sort.Slice(report.Failed, func(i, j int) bool {
+		return report.Failed[i].Address < report.Failed[j].Address
+	})
+	sort.Slice(report.Unverified, func(i, j int) bool {
+		return report.Unverified[i].Address < report.Unverified[j].Address
+	})
+
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	path := filepath.Join(directory, defaultVerifyReportFile)
+	log.Printf("Writing verify report to %s", path)
+	if err := writeToFile(reportJSON, path); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Returns the attributes that would be changed by a plan, sorted by attribute
+func getVerifyChanges(instanceDiff *terraform.InstanceDiff) []verifyAttributeChange {
+	if instanceDiff.Empty() {
+		return nil
+	}
+	var changes []verifyAttributeChange
+	for attr, attrDiff := range instanceDiff.Attributes {
+		if attrDiff == nil || (attrDiff.Old == attrDiff.New && !attrDiff.NewComputed && !attrDiff.NewRemoved && !attrDiff.RequiresNew) {
+			continue
+		}
+		change := verifyAttributeChange{
+			Attribute:   attr,
+			State:       attrDiff.Old,
+			Config:      attrDiff.New,
+			RequiresNew: attrDiff.RequiresNew,
+		}
+		if attrDiff.NewRemoved {
+			change.Config = "(removed)"
+		}
+		if attrDiff.Sensitive {
+			change.State = "(sensitive)"
+			change.Config = "(sensitive)"
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Attribute < changes[j].Attribute
+	})
+	return changes
+}
@@ -62,7 +62,11 @@ func getAllArchitectDatatables(_ context.Context, clientConfig *platformclientv2
 		}
 
 		for _, table := range *tables.Entities {
-			resources[*table.Id] = &ResourceMeta{Name: *table.Name}
+			resourceMeta := &ResourceMeta{Name: *table.Name}
+			if table.Division != nil && table.Division.Id != nil {
+				resourceMeta.DivisionID = *table.Division.Id
+			}
+			resources[*table.Id] = resourceMeta
 		}
 	}
 
@@ -72,6 +76,7 @@ func getAllArchitectDatatables(_ context.Context, clientConfig *platformclientv2
 func architectDatatableExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllArchitectDatatables),
+		DivisionAware:    true,
 		RefAttrs: map[string]*RefAttrSettings{
 			"division_id": {RefType: "genesyscloud_auth_division"},
 		},
@@ -52,7 +52,8 @@ func getAllArchitectDatatableRows(ctx context.Context, clientConfig *platformcli
 			for _, row := range *rows.Entities {
 				if keyVal, ok := row["key"]; ok {
 					keyStr := keyVal.(string) // Keys must be strings
-					resources[createDatatableRowId(tableId, keyStr)] = &ResourceMeta{Name: tableMeta.Name + "_" + keyStr}
+					// Rows are in the division of their table
+					resources[createDatatableRowId(tableId, keyStr)] = &ResourceMeta{Name: tableMeta.Name + "_" + keyStr, DivisionID: tableMeta.DivisionID}
 				}
 			}
 		}
@@ -64,9 +65,11 @@ func getAllArchitectDatatableRows(ctx context.Context, clientConfig *platformcli
 func architectDatatableRowExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllArchitectDatatableRows),
+		DivisionAware:    true,
 		RefAttrs: map[string]*RefAttrSettings{
 			"datatable_id": {RefType: "genesyscloud_architect_datatable"},
 		},
+		JsonEncodeAttributes: []string{"properties_json"},
 	}
 }
 
@@ -103,7 +106,7 @@ func resourceArchitectDatatableRow() *schema.Resource {
 				DiffSuppressFunc: suppressEquivalentJsonDiffs,
 			},
 		},
-		CustomizeDiff: customizeDatatableRowDiff,
+		CustomizeDiff: customizeDiffWithPooledClient(customizeDatatableRowDiff),
 	}
 }
 
@@ -268,7 +271,7 @@ func customizeDatatableRowDiff(_ context.Context, diff *schema.ResourceDiff, met
 	archAPI := platformclientv2.NewArchitectApiWithConfig(sdkConfig)
 
 	// Retrieve defaults from the datatable for this row
-	datatable, getErr := getArchitectDatatableCached(tableId, archAPI)
+	datatable, getErr := getArchitectDatatableCached(tableId, archAPI, meta.(*providerMeta).DatatableCache)
 	if getErr != nil {
 		return fmt.Errorf("Failed to read datatable %s: %s", tableId, getErr)
 	}
@@ -318,10 +321,8 @@ func customizeDatatableRowDiff(_ context.Context, diff *schema.ResourceDiff, met
 }
 
 // Prevent getting the datatable schema on every row diff
-// by caching the results for the duration of the TF run
-var archDatatableCache sync.Map
-
-func getArchitectDatatableCached(tableID string, archAPI *platformclientv2.ArchitectApi) (*Datatable, error) {
+// by caching the results in the provider's cache for the duration of the TF run
+func getArchitectDatatableCached(tableID string, archAPI *platformclientv2.ArchitectApi, archDatatableCache *sync.Map) (*Datatable, error) {
 	if table, ok := archDatatableCache.Load(tableID); ok {
 		return table.(*Datatable), nil
 	}
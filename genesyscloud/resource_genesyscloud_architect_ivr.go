@@ -29,7 +29,7 @@ func getAllIvrConfigs(_ context.Context, clientConfig *platformclientv2.Configur
 
 		for _, ivrConfig := range *ivrConfigs.Entities {
 			if *ivrConfig.State != "deleted" {
-				resources[*ivrConfig.Id] = &ResourceMeta{Name: *ivrConfig.Name}
+				resources[*ivrConfig.Id] = &ResourceMeta{Name: *ivrConfig.Name, DateModified: ivrConfig.DateModified, DivisionID: getDivisionID(ivrConfig.Division)}
 			}
 		}
 	}
@@ -40,10 +40,14 @@ func getAllIvrConfigs(_ context.Context, clientConfig *platformclientv2.Configur
 func architectIvrExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllIvrConfigs),
+		DivisionAware:    true,
 		RefAttrs: map[string]*RefAttrSettings{
-			"open_hours_flow_id":    {RefType: "genesyscloud_flow"},
-			"closed_hours_flow_id":  {RefType: "genesyscloud_flow"},
-			"holiday_hours_flow_id": {RefType: "genesyscloud_flow"},
+			"open_hours_flow_id":    {RefType: "genesyscloud_flow", DataSource: "genesyscloud_flow"},
+			"closed_hours_flow_id":  {RefType: "genesyscloud_flow", DataSource: "genesyscloud_flow"},
+			"holiday_hours_flow_id": {RefType: "genesyscloud_flow", DataSource: "genesyscloud_flow"},
+		},
+		VariableAttrs: map[string]*VariableAttrSettings{
+			"dnis": {},
 		},
 	}
 }
@@ -207,7 +211,7 @@ func updateIvrConfig(ctx context.Context, d *schema.ResourceData, meta interface
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	architectApi := platformclientv2.NewArchitectApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get current version
 		ivr, resp, getErr := architectApi.GetArchitectIvr(d.Id())
 		if getErr != nil {
@@ -28,7 +28,7 @@ func getAllArchitectScheduleGroups(_ context.Context, clientConfig *platformclie
 		}
 
 		for _, scheduleGroup := range *scheduleGroups.Entities {
-			resources[*scheduleGroup.Id] = &ResourceMeta{Name: *scheduleGroup.Name}
+			resources[*scheduleGroup.Id] = &ResourceMeta{Name: *scheduleGroup.Name, DateModified: scheduleGroup.DateModified, DivisionID: getDivisionID(scheduleGroup.Division)}
 		}
 	}
 
@@ -38,6 +38,7 @@ func getAllArchitectScheduleGroups(_ context.Context, clientConfig *platformclie
 func architectScheduleGroupsExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllArchitectScheduleGroups),
+		DivisionAware:    true,
 		RefAttrs:         map[string]*RefAttrSettings{}, // No references
 	}
 }
@@ -180,7 +181,7 @@ func updateArchitectScheduleGroups(ctx context.Context, d *schema.ResourceData,
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	archAPI := platformclientv2.NewArchitectApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get current schedule group version
 		scheduleGroup, resp, getErr := archAPI.GetArchitectSchedulegroup(d.Id())
 		if getErr != nil {
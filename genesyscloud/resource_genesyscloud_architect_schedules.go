@@ -29,7 +29,7 @@ func getAllArchitectSchedules(_ context.Context, clientConfig *platformclientv2.
 		}
 
 		for _, schedule := range *schedules.Entities {
-			resources[*schedule.Id] = &ResourceMeta{Name: *schedule.Name}
+			resources[*schedule.Id] = &ResourceMeta{Name: *schedule.Name, DateModified: schedule.DateModified, DivisionID: getDivisionID(schedule.Division)}
 		}
 	}
 
@@ -39,6 +39,7 @@ func getAllArchitectSchedules(_ context.Context, clientConfig *platformclientv2.
 func architectSchedulesExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllArchitectSchedules),
+		DivisionAware:    true,
 		RefAttrs:         map[string]*RefAttrSettings{}, // No references
 	}
 }
@@ -200,7 +201,7 @@ func updateArchitectSchedules(ctx context.Context, d *schema.ResourceData, meta
 		return diag.Errorf("Failed to parse date %s: %s", end, err)
 	}
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get current schedule version
 		sched, resp, getErr := archAPI.GetArchitectSchedule(d.Id())
 		if getErr != nil {
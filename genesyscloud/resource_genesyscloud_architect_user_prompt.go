@@ -9,7 +9,9 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -20,6 +22,9 @@ import (
 	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
 )
 
+// Directory in an export where prompt audio files are downloaded
+const promptsExportDir = "prompts"
+
 var userPromptResource = &schema.Resource{
 	Schema: map[string]*schema.Schema{
 		"language": {
@@ -713,6 +718,96 @@ func architectUserPromptExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllUserPrompts),
 		RefAttrs:         map[string]*RefAttrSettings{}, // No references
+		ExportFilesFunc:  exportFilesWithPooledClient(exportUserPromptFiles),
+		RestoreFilesFunc: restoreUserPromptFiles,
+	}
+}
+
+// Downloads the audio of each prompt resource and updates its filename to the downloaded file.
+// Resources are downloaded whether or not they were uploaded with a filename, e.g. audio uploaded in the UI.
+func exportUserPromptFiles(ctx context.Context, id string, resName string, configMap jsonMap, exportDir string, clientConfig *platformclientv2.Configuration) diag.Diagnostics {
+	configResources, _ := configMap["resources"].([]interface{})
+	if len(configResources) == 0 {
+		return nil
+	}
+
+	architectAPI := platformclientv2.NewArchitectApiWithConfig(clientConfig)
+	userPrompt, _, err := architectAPI.GetArchitectPrompt(id)
+	if err != nil {
+		return diag.Errorf("Failed to get user prompt %s: %s", id, err)
+	}
+	if userPrompt.Resources == nil {
+		return nil
+	}
+
+	promptAssets := make(map[string]platformclientv2.Promptasset, len(*userPrompt.Resources))
+	for _, promptAsset := range *userPrompt.Resources {
+		if promptAsset.Language != nil && promptAsset.MediaUri != nil && *promptAsset.MediaUri != "" {
+			promptAssets[*promptAsset.Language] = promptAsset
+		}
+	}
+
+	// Keep downloading the other languages if one fails, so a single error does not lose all of the audio
+	var diagErr diag.Diagnostics
+	for _, configResource := range configResources {
+		resourceMap, ok := configResource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		language, _ := resourceMap["language"].(string)
+		promptAsset, ok := promptAssets[language]
+		if !ok {
+			continue
+		}
+
+		exportFilename := filepath.Join(promptsExportDir, fmt.Sprintf("%s-%s%s", resName, language, getPromptAssetExt(promptAsset)))
+		if err := downloadExportFile(ctx, *promptAsset.MediaUri, filepath.Join(exportDir, exportFilename)); err != nil {
+			diagErr = append(diagErr, diag.Errorf("Failed to download %s audio for user prompt %s: %s", language, id, err)...)
+			continue
+		}
+		resourceMap["filename"] = filepath.ToSlash(exportFilename)
+	}
+	return diagErr
+}
+
+// Returns the extension of the audio of a prompt resource from its uploaded filename or media URI, defaulting to .wav
+func getPromptAssetExt(promptAsset platformclientv2.Promptasset) string {
+	if promptAsset.Tags != nil {
+		if filenames := (*promptAsset.Tags)["filename"]; len(filenames) > 0 && filepath.Ext(filenames[0]) != "" {
+			return filepath.Ext(filenames[0])
+		}
+	}
+	if mediaURL, err := url.Parse(*promptAsset.MediaUri); err == nil && path.Ext(mediaURL.Path) != "" {
+		return path.Ext(mediaURL.Path)
+	}
+	return ".wav"
+}
+
+// Sets the filename of each prompt resource in an exported config to its value in the org, so the paths of
+// downloaded audio files are not compared with the org
+func restoreUserPromptFiles(exported map[string]interface{}, current map[string]interface{}) {
+	currentFilenames := make(map[string]string)
+	currentResources, _ := current["resources"].([]interface{})
+	for _, currentResource := range currentResources {
+		if resourceMap, ok := currentResource.(map[string]interface{}); ok {
+			language, _ := resourceMap["language"].(string)
+			filename, _ := resourceMap["filename"].(string)
+			currentFilenames[language] = filename
+		}
+	}
+
+	exportedResources, _ := exported["resources"].([]interface{})
+	for _, exportedResource := range exportedResources {
+		resourceMap, ok := exportedResource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		language, _ := resourceMap["language"].(string)
+		if filename := currentFilenames[language]; filename != "" {
+			resourceMap["filename"] = filename
+		} else {
+			delete(resourceMap, "filename")
+		}
 	}
 }
 
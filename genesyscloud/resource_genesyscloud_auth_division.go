@@ -28,7 +28,8 @@ func getAllAuthDivisions(_ context.Context, clientConfig *platformclientv2.Confi
 		}
 
 		for _, division := range *divisions.Entities {
-			resources[*division.Id] = &ResourceMeta{Name: *division.Name}
+			// Divisions are filtered to the exported divisions
+			resources[*division.Id] = &ResourceMeta{Name: *division.Name, DivisionID: *division.Id}
 		}
 	}
 
@@ -38,6 +39,7 @@ func getAllAuthDivisions(_ context.Context, clientConfig *platformclientv2.Confi
 func authDivisionExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllAuthDivisions),
+		DivisionAware:    true,
 		RefAttrs:         map[string]*RefAttrSettings{}, // No references
 	}
 }
@@ -85,7 +87,7 @@ func createAuthDivision(ctx context.Context, d *schema.ResourceData, meta interf
 
 	if home {
 		// Home division must already exist, or it cannot be modified
-		id, diagErr := getHomeDivisionID()
+		id, diagErr := getHomeDivisionID(meta)
 		if diagErr != nil {
 			return diagErr
 		}
@@ -148,7 +148,7 @@ func validateHomeDivisionID(divResourceName string) resource.TestCheckFunc {
 			return fmt.Errorf("Failed to find division %s in state", divResourceName)
 		}
 		divID := divResource.Primary.ID
-		homeDivID, err := getHomeDivisionID()
+		homeDivID, err := getTestHomeDivisionID()
 		if err != nil {
 			return fmt.Errorf("%v", err)
 		}
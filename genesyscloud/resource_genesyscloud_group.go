@@ -56,6 +56,7 @@ func getAllGroups(_ context.Context, clientConfig *platformclientv2.Configuratio
 		}
 
 		for _, group := range *groups.Entities {
+			// Members are changed without updating the group's modification date, so it is not set
 			resources[*group.Id] = &ResourceMeta{Name: *group.Name}
 		}
 	}
@@ -174,7 +175,7 @@ func createGroup(ctx context.Context, d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	diagErr := updateGroupMembers(d, groupsAPI)
+	diagErr := updateGroupMembers(ctx, d, groupsAPI)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -241,7 +242,7 @@ func updateGroup(ctx context.Context, d *schema.ResourceData, meta interface{})
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	groupsAPI := platformclientv2.NewGroupsApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get current group version
 		group, resp, getErr := groupsAPI.GetGroup(d.Id())
 		if getErr != nil {
@@ -267,7 +268,7 @@ func updateGroup(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diagErr
 	}
 
-	diagErr = updateGroupMembers(d, groupsAPI)
+	diagErr = updateGroupMembers(ctx, d, groupsAPI)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -283,7 +284,7 @@ func deleteGroup(ctx context.Context, d *schema.ResourceData, meta interface{})
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	groupsAPI := platformclientv2.NewGroupsApiWithConfig(sdkConfig)
 
-	retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Directory occasionally returns version errors on deletes if an object was updated at the same time.
 		log.Printf("Deleting group %s", name)
 		resp, err := groupsAPI.DeleteGroup(d.Id())
@@ -403,7 +404,7 @@ func flattenGroupOwners(owners []platformclientv2.User) *schema.Set {
 	return schema.NewSet(schema.HashString, interfaceList)
 }
 
-func updateGroupMembers(d *schema.ResourceData, groupsAPI *platformclientv2.GroupsApi) diag.Diagnostics {
+func updateGroupMembers(ctx context.Context, d *schema.ResourceData, groupsAPI *platformclientv2.GroupsApi) diag.Diagnostics {
 	if d.HasChange("member_ids") {
 		if membersConfig := d.Get("member_ids"); membersConfig != nil {
 			// Get existing members
@@ -422,7 +423,7 @@ func updateGroupMembers(d *schema.ResourceData, groupsAPI *platformclientv2.Grou
 
 			membersToRemove := sliceDifference(existingMembers, configMembers)
 			if len(membersToRemove) > 0 {
-				if diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+				if diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 					_, resp, err := groupsAPI.DeleteGroupMembers(d.Id(), strings.Join(membersToRemove, ","))
 					if err != nil {
 						return resp, diag.Errorf("Failed to remove members from group %s: %s", d.Id(), err)
@@ -435,7 +436,7 @@ func updateGroupMembers(d *schema.ResourceData, groupsAPI *platformclientv2.Grou
 
 			membersToAdd := sliceDifference(configMembers, existingMembers)
 			if len(membersToAdd) > 0 {
-				if diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+				if diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 					// Need the current group version to add members
 					groupInfo, _, getErr := groupsAPI.GetGroup(d.Id())
 					if getErr != nil {
@@ -81,6 +81,7 @@ func integrationExporter() *ResourceExporter {
 		RefAttrs: map[string]*RefAttrSettings{
 			"config.credentials.*": {RefType: "genesyscloud_integration_credential"},
 		},
+		JsonEncodeAttributes: []string{"config.properties", "config.advanced"},
 	}
 }
 
@@ -144,7 +145,7 @@ func createIntegration(ctx context.Context, d *schema.ResourceData, meta interfa
 	d.SetId(*integration.Id)
 
 	//Update integration config separately
-	diagErr, name := updateIntegrationConfig(d, integrationAPI)
+	diagErr, name := updateIntegrationConfig(ctx, d, integrationAPI)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -217,7 +218,7 @@ func updateIntegration(ctx context.Context, d *schema.ResourceData, meta interfa
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	integrationAPI := platformclientv2.NewIntegrationsApiWithConfig(sdkConfig)
 
-	diagErr, name := updateIntegrationConfig(d, integrationAPI)
+	diagErr, name := updateIntegrationConfig(ctx, d, integrationAPI)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -325,7 +326,7 @@ func flattenConfigCredentials(credentials map[string]platformclientv2.Credential
 	return results
 }
 
-func updateIntegrationConfig(d *schema.ResourceData, integrationAPI *platformclientv2.IntegrationsApi) (diag.Diagnostics, string) {
+func updateIntegrationConfig(ctx context.Context, d *schema.ResourceData, integrationAPI *platformclientv2.IntegrationsApi) (diag.Diagnostics, string) {
 	if d.HasChange("config") {
 		if configInput := d.Get("config").([]interface{}); configInput != nil {
 
@@ -364,7 +365,7 @@ func updateIntegrationConfig(d *schema.ResourceData, integrationAPI *platformcli
 				credential = buildConfigCredentials(configMap["credentials"].(map[string]interface{}))
 			}
 
-			diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+			diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 
 				// Get latest config version
 				integrationConfig, resp, err := integrationAPI.GetIntegrationConfigCurrent(d.Id())
@@ -103,6 +103,7 @@ func integrationActionExporter() *ResourceExporter {
 		RefAttrs: map[string]*RefAttrSettings{
 			"integration_id": {RefType: "genesyscloud_integration"},
 		},
+		JsonEncodeAttributes: []string{"contract_input", "contract_output"},
 	}
 }
 
@@ -317,7 +318,7 @@ func updateIntegrationAction(ctx context.Context, d *schema.ResourceData, meta i
 
 	log.Printf("Updating integration action %s", name)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get the latest action version to send with PATCH
 		action, resp, getErr := sdkGetIntegrationAction(d.Id(), integAPI)
 		if getErr != nil {
@@ -29,7 +29,7 @@ func getAllCredentials(_ context.Context, clientConfig *platformclientv2.Configu
 
 		for _, cred := range *credentials.Entities {
 			if cred.Name != nil { // Credential is possible to have no name
-				resources[*cred.Id] = &ResourceMeta{Name: *cred.Name}
+				resources[*cred.Id] = &ResourceMeta{Name: *cred.Name, DateModified: cred.ModifiedDate}
 			}
 		}
 	}
@@ -41,6 +41,9 @@ func credentialExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllCredentials),
 		RefAttrs:         map[string]*RefAttrSettings{}, // No Reference
+		VariableAttrs: map[string]*VariableAttrSettings{
+			"fields": {Sensitive: true},
+		},
 	}
 }
 
@@ -49,7 +49,7 @@ func getAllOAuthClients(_ context.Context, clientConfig *platformclientv2.Config
 			// Don't include clients disabled by support
 			continue
 		}
-		resources[*client.Id] = &ResourceMeta{Name: *client.Name}
+		resources[*client.Id] = &ResourceMeta{Name: *client.Name, DateModified: client.DateModified}
 	}
 
 	return resources, nil
@@ -65,6 +65,9 @@ func oauthClientExporter() *ResourceExporter {
 		RemoveIfMissing: map[string][]string{
 			"roles": {"role_id"},
 		},
+		VariableAttrs: map[string]*VariableAttrSettings{
+			"registered_redirect_uris": {},
+		},
 	}
 }
 
@@ -143,7 +146,7 @@ func createOAuthClient(ctx context.Context, d *schema.ResourceData, meta interfa
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	oauthAPI := platformclientv2.NewOAuthApiWithConfig(sdkConfig)
 
-	roles, diagErr := buildOAuthRoles(d)
+	roles, diagErr := buildOAuthRoles(d, meta)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -242,7 +245,7 @@ func updateOAuthClient(ctx context.Context, d *schema.ResourceData, meta interfa
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	oauthAPI := platformclientv2.NewOAuthApiWithConfig(sdkConfig)
 
-	roles, diagErr := buildOAuthRoles(d)
+	roles, diagErr := buildOAuthRoles(d, meta)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -323,7 +326,7 @@ func buildOAuthScopes(d *schema.ResourceData) *[]string {
 	return nil
 }
 
-func buildOAuthRoles(d *schema.ResourceData) (*[]platformclientv2.Roledivision, diag.Diagnostics) {
+func buildOAuthRoles(d *schema.ResourceData, meta interface{}) (*[]platformclientv2.Roledivision, diag.Diagnostics) {
 	if config, ok := d.GetOk("roles"); ok {
 		var sdkRoles []platformclientv2.Roledivision
 		roleConfig := config.(*schema.Set).List()
@@ -339,7 +342,7 @@ func buildOAuthRoles(d *schema.ResourceData) (*[]platformclientv2.Roledivision,
 			if divisionId == "" {
 				// Set to home division if not set
 				var diagErr diag.Diagnostics
-				divisionId, diagErr = getHomeDivisionID()
+				divisionId, diagErr = getHomeDivisionID(meta)
 				if diagErr != nil {
 					return nil, diagErr
 				}
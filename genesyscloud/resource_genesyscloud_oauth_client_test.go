@@ -163,7 +163,7 @@ func validateOauthRole(resourceName string, roleResourceName string, division st
 
 		if division == "" {
 			// If no division specified, role should be in the home division
-			homeDiv, err := getHomeDivisionID()
+			homeDiv, err := getTestHomeDivisionID()
 			if err != nil {
 				return fmt.Errorf("Failed to query home div: %v", err)
 			}
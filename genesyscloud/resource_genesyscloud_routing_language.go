@@ -29,7 +29,7 @@ func getAllRoutingLanguages(_ context.Context, clientConfig *platformclientv2.Co
 
 		for _, language := range *languages.Entities {
 			if *language.State != "deleted" {
-				resources[*language.Id] = &ResourceMeta{Name: *language.Name}
+				resources[*language.Id] = &ResourceMeta{Name: *language.Name, DateModified: language.DateModified}
 			}
 		}
 	}
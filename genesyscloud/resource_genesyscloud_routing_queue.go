@@ -86,7 +86,8 @@ func getAllRoutingQueues(_ context.Context, clientConfig *platformclientv2.Confi
 		}
 
 		for _, queue := range *queues.Entities {
-			resources[*queue.Id] = &ResourceMeta{Name: *queue.Name}
+			// Members and wrapup codes are changed without updating the queue's modification date, so it is not set
+			resources[*queue.Id] = &ResourceMeta{Name: *queue.Name, DivisionID: getDivisionID(queue.Division)}
 		}
 	}
 
@@ -96,12 +97,13 @@ func getAllRoutingQueues(_ context.Context, clientConfig *platformclientv2.Confi
 func routingQueueExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllRoutingQueues),
+		DivisionAware:    true,
 		RefAttrs: map[string]*RefAttrSettings{
 			"division_id":                       {RefType: "genesyscloud_auth_division"},
-			"queue_flow_id":                     {}, // Ref type not yet defined
+			"queue_flow_id":                     {RefType: "genesyscloud_flow", DataSource: "genesyscloud_flow"},
 			"whisper_prompt_id":                 {}, // Ref type not yet defined
 			"outbound_messaging_sms_address_id": {}, // Ref type not yet defined
-			"default_script_ids.*":              {}, // Ref type not yet defined
+			"default_script_ids.*":              {RefType: "genesyscloud_script", DataSource: "genesyscloud_script"},
 			"outbound_email_address.route_id":   {RefType: "genesyscloud_routing_email_route"},
 			"outbound_email_address.domain_id":  {RefType: "genesyscloud_routing_email_domain"},
 			"bullseye_rings.skills_to_remove":   {RefType: "genesyscloud_routing_skill"},
@@ -621,7 +623,7 @@ func updateQueue(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.Errorf("Error updating queue %s: %s", name, err)
 	}
 
-	diagErr := updateObjectDivision(d, "QUEUE", sdkConfig)
+	diagErr := updateObjectDivision(d, "QUEUE", meta)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -29,7 +29,7 @@ func getAllRoutingSkills(_ context.Context, clientConfig *platformclientv2.Confi
 
 		for _, skill := range *skills.Entities {
 			if *skill.State != "deleted" {
-				resources[*skill.Id] = &ResourceMeta{Name: *skill.Name}
+				resources[*skill.Id] = &ResourceMeta{Name: *skill.Name, DateModified: skill.DateModified}
 			}
 		}
 	}
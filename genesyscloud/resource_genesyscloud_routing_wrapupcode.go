@@ -28,7 +28,7 @@ func getAllRoutingWrapupCodes(_ context.Context, clientConfig *platformclientv2.
 		}
 
 		for _, wrapupcode := range *wrapupcodes.Entities {
-			resources[*wrapupcode.Id] = &ResourceMeta{Name: *wrapupcode.Name}
+			resources[*wrapupcode.Id] = &ResourceMeta{Name: *wrapupcode.Name, DateModified: wrapupcode.DateModified}
 		}
 	}
 
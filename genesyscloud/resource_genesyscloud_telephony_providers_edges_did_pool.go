@@ -30,7 +30,7 @@ func getAllDidPools(_ context.Context, clientConfig *platformclientv2.Configurat
 
 		for _, didPool := range *didPools.Entities {
 			if *didPool.State != "deleted" {
-				resources[*didPool.Id] = &ResourceMeta{Name: *didPool.StartPhoneNumber}
+				resources[*didPool.Id] = &ResourceMeta{Name: *didPool.StartPhoneNumber, DateModified: didPool.DateModified}
 			}
 		}
 	}
@@ -117,7 +117,7 @@ func updateEdgeGroup(ctx context.Context, d *schema.ResourceData, meta interface
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	edgesAPI := platformclientv2.NewTelephonyProvidersEdgeApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		edgeGroupFromApi, resp, getErr := edgesAPI.GetTelephonyProvidersEdgesEdgegroup(d.Id(), nil)
 		if getErr != nil {
 			if isStatus404(resp) {
@@ -237,7 +237,7 @@ func getAllEdgeGroups(_ context.Context, sdkConfig *platformclientv2.Configurati
 
 		for _, edgeGroup := range *edgeGroups.Entities {
 			if *edgeGroup.State != "deleted" {
-				resources[*edgeGroup.Id] = &ResourceMeta{Name: *edgeGroup.Name}
+				resources[*edgeGroup.Id] = &ResourceMeta{Name: *edgeGroup.Name, DateModified: edgeGroup.DateModified}
 			}
 		}
 	}
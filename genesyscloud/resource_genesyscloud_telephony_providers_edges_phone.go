@@ -457,7 +457,7 @@ func getAllPhones(_ context.Context, sdkConfig *platformclientv2.Configuration)
 
 		for _, phone := range *phones.Entities {
 			if *phone.State != "deleted" {
-				resources[*phone.Id] = &ResourceMeta{Name: *phone.Name}
+				resources[*phone.Id] = &ResourceMeta{Name: *phone.Name, DateModified: phone.DateModified}
 			}
 		}
 	}
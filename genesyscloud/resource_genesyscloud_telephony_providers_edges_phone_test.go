@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
 	"math/rand"
-	"os"
 	"strconv"
 	"strings"
 	"testing"
@@ -314,16 +313,10 @@ func getDefaultSiteId() (string, error) {
 }
 
 func authorizeSdk() error {
-	// Create new config
-	sdkConfig = platformclientv2.GetDefaultConfiguration()
-
-	sdkConfig.BasePath = getRegionBasePath(os.Getenv("GENESYSCLOUD_REGION"))
-
-	err := sdkConfig.AuthorizeClientCredentials(os.Getenv("GENESYSCLOUD_OAUTHCLIENT_ID"), os.Getenv("GENESYSCLOUD_OAUTHCLIENT_SECRET"))
-	if err != nil {
-		return err
+	if err := authorizeTestDefaultConfig(); err != nil {
+		return fmt.Errorf("%s", diagnosticsString(err))
 	}
-
+	sdkConfig = platformclientv2.GetDefaultConfiguration()
 	return nil
 }
 
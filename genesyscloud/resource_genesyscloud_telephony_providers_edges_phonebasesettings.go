@@ -61,7 +61,7 @@ func resourcePhoneBaseSettings() *schema.Resource {
 				Computed:    true,
 			},
 		},
-		CustomizeDiff: customizePhoneBaseSettingsPropertiesDiff,
+		CustomizeDiff: customizeDiffWithPooledClient(customizePhoneBaseSettingsPropertiesDiff),
 	}
 }
 
@@ -247,7 +247,7 @@ func getAllPhoneBaseSettings(_ context.Context, sdkConfig *platformclientv2.Conf
 
 		for _, phoneBaseSetting := range *phoneBaseSettings.Entities {
 			if *phoneBaseSetting.State != "deleted" {
-				resources[*phoneBaseSetting.Id] = &ResourceMeta{Name: *phoneBaseSetting.Name}
+				resources[*phoneBaseSetting.Id] = &ResourceMeta{Name: *phoneBaseSetting.Name, DateModified: phoneBaseSetting.DateModified}
 			}
 		}
 	}
@@ -257,7 +257,8 @@ func getAllPhoneBaseSettings(_ context.Context, sdkConfig *platformclientv2.Conf
 
 func phoneBaseSettingsExporter() *ResourceExporter {
 	return &ResourceExporter{
-		GetResourcesFunc: getAllWithPooledClient(getAllPhoneBaseSettings),
-		RefAttrs:         map[string]*RefAttrSettings{},
+		GetResourcesFunc:     getAllWithPooledClient(getAllPhoneBaseSettings),
+		RefAttrs:             map[string]*RefAttrSettings{},
+		JsonEncodeAttributes: []string{"properties"},
 	}
 }
@@ -221,6 +221,7 @@ func getSites(_ context.Context, sdkConfig *platformclientv2.Configuration) (Res
 
 		for _, site := range *sites.Entities {
 			if *site.State != "deleted" {
+				// Number plans and outbound routes are changed without updating the site's modification date, so it is not set
 				resources[*site.Id] = &ResourceMeta{Name: *site.Name}
 			}
 		}
@@ -384,7 +385,7 @@ func updateSite(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 	edgesAPI := platformclientv2.NewTelephonyProvidersEdgeApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get current site version
 		currentSite, resp, getErr := edgesAPI.GetTelephonyProvidersEdgesSite(d.Id())
 		if getErr != nil {
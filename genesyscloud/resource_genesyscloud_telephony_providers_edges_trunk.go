@@ -207,7 +207,7 @@ func getAllTrunks(ctx context.Context, sdkConfig *platformclientv2.Configuration
 
 		for _, trunk := range *trunks.Entities {
 			if *trunk.State != "deleted" {
-				resources[*trunk.Id] = &ResourceMeta{Name: *trunk.Name}
+				resources[*trunk.Id] = &ResourceMeta{Name: *trunk.Name, DateModified: trunk.DateModified}
 			}
 		}
 	}
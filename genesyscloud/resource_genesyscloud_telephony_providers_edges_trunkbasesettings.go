@@ -65,7 +65,7 @@ func resourceTrunkBaseSettings() *schema.Resource {
 				Optional:    true,
 			},
 		},
-		CustomizeDiff: customizeTrunkBaseSettingsPropertiesDiff,
+		CustomizeDiff: customizeDiffWithPooledClient(customizeTrunkBaseSettingsPropertiesDiff),
 	}
 }
 
@@ -131,7 +131,7 @@ func updateTrunkBaseSettings(ctx context.Context, d *schema.ResourceData, meta i
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	edgesAPI := platformclientv2.NewTelephonyProvidersEdgeApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Get the latest version of the setting
 		trunkBaseSettings, resp, getErr := edgesAPI.GetTelephonyProvidersEdgesTrunkbasesetting(d.Id(), true)
 		if getErr != nil {
@@ -221,7 +221,7 @@ func deleteTrunkBaseSettings(ctx context.Context, d *schema.ResourceData, meta i
 	sdkConfig := meta.(*providerMeta).ClientConfig
 	edgesAPI := platformclientv2.NewTelephonyProvidersEdgeApiWithConfig(sdkConfig)
 
-	diagErr := retryWhen(isStatus400, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	diagErr := retryWhen(ctx, isStatus400, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		log.Printf("Deleting trunk base settings")
 		resp, err := edgesAPI.DeleteTelephonyProvidersEdgesTrunkbasesetting(d.Id())
 		if err != nil {
@@ -272,7 +272,7 @@ func getAllTrunkBaseSettings(ctx context.Context, sdkConfig *platformclientv2.Co
 
 		for _, trunkBaseSetting := range *trunkBaseSettings.Entities {
 			if *trunkBaseSetting.State != "deleted" {
-				resources[*trunkBaseSetting.Id] = &ResourceMeta{Name: *trunkBaseSetting.Name}
+				resources[*trunkBaseSetting.Id] = &ResourceMeta{Name: *trunkBaseSetting.Name, DateModified: trunkBaseSetting.DateModified}
 			}
 		}
 	}
@@ -282,7 +282,8 @@ func getAllTrunkBaseSettings(ctx context.Context, sdkConfig *platformclientv2.Co
 
 func trunkBaseSettingsExporter() *ResourceExporter {
 	return &ResourceExporter{
-		GetResourcesFunc: getAllWithPooledClient(getAllTrunkBaseSettings),
-		RefAttrs:         map[string]*RefAttrSettings{},
+		GetResourcesFunc:     getAllWithPooledClient(getAllTrunkBaseSettings),
+		RefAttrs:             map[string]*RefAttrSettings{},
+		JsonEncodeAttributes: []string{"properties"},
 	}
 }
@@ -4,16 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/google/uuid"
-	"hash/fnv"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -24,6 +23,7 @@ import (
 
 const (
 	defaultTfJSONFile  = "genesyscloud.tf.json"
+	defaultTfHCLFile   = "genesyscloud.tf"
 	defaultTfStateFile = "terraform.tfstate"
 )
 
@@ -31,8 +31,8 @@ func resourceTfExport() *schema.Resource {
 	return &schema.Resource{
 		Description: fmt.Sprintf(`
 		Genesys Cloud Resource to export Terraform config and (optionally) tfstate files to a local directory. 
-		The config file is named '%s', and the state file is named '%s'.
-		`, defaultTfJSONFile, defaultTfStateFile),
+		The config file is named '%s' or '%s', and the state file is named '%s'.
+		`, defaultTfJSONFile, defaultTfHCLFile, defaultTfStateFile),
 
 		CreateContext: createTfExport,
 		ReadContext:   readTfExport,
@@ -56,15 +56,72 @@ func resourceTfExport() *schema.Resource {
 					Type:         schema.TypeString,
 					ValidateFunc: validation.StringInSlice(getAvailableExporterTypes(), false),
 				},
+				ForceNew:      true,
+				ConflictsWith: []string{"include_filter_resources", "root_resources"},
+			},
+			"include_filter_resources": {
+				Description: "Include only resources that match either a resource type or a resource type::regular expression, e.g. 'genesyscloud_routing_queue::^Sales_'. Expressions are matched against the names of the objects in Genesys Cloud.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateExportFilter,
+				},
+				ForceNew:      true,
+				ConflictsWith: []string{"resource_types", "root_resources"},
+			},
+			"exclude_filter_resources": {
+				Description: "Exclude resources that match either a resource type or a resource type::regular expression, e.g. 'genesyscloud_user::^test_'. Expressions are matched against the names of the objects in Genesys Cloud.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateExportFilter,
+				},
 				ForceNew: true,
 			},
+			"root_resources": {
+				Description: "Export only these resources and every resource they reference directly or indirectly. Each value should be of the form {resource_type}::{id}, e.g. 'genesyscloud_architect_ivr::<id>'. References to types that cannot be exported are reported as a warning.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateRootResource,
+				},
+				ForceNew:      true,
+				ConflictsWith: []string{"resource_types", "include_filter_resources"},
+			},
 			"include_state_file": {
-				Description: "Export a 'terraform.tfstate' file along with the config file. This can be used for orgs to begin managing existing resources with terraform.",
-				Type:        schema.TypeBool,
+				Description:   "Export a 'terraform.tfstate' file along with the config file. This can be used for orgs to begin managing existing resources with terraform.",
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ForceNew:      true,
+				ConflictsWith: []string{"import_mode"},
+			},
+			"import_mode": {
+				Description:   fmt.Sprintf("Export the imports needed to begin managing existing resources with terraform instead of a state file (%s | %s). '%s' writes terraform import blocks to '%s' or '%s', which requires Terraform 1.5 or later. '%s' writes the shell script '%s' of terraform import commands.", importModeBlocks, importModeScript, importModeBlocks, getConfigFileName(defaultImportsFile, false), getConfigFileName(defaultImportsFile, true), importModeScript, defaultImportScriptFile),
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.StringInSlice([]string{importModeBlocks, importModeScript}, false),
+				ConflictsWith: []string{"include_state_file"},
+			},
+			"division_ids": {
+				Description: "Only export the resources in these divisions. Resource types that are not division-aware are skipped unless include_divisionless_types is set. Divisions are exported if they are in the list.",
+				Type:        schema.TypeList,
 				Optional:    true,
-				Default:     false,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotEmpty},
 				ForceNew:    true,
 			},
+			"include_divisionless_types": {
+				Description:  "Export all resources of the types that are not division-aware when filtering by division_ids.",
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				ForceNew:     true,
+				RequiredWith: []string{"division_ids"},
+			},
 			"exclude_attributes": {
 				Description: "Attributes to exclude from the config when exporting resources. Each value should be of the form {resource_name}.{attribute}, e.g. 'genesyscloud_user.skills'. Excluded attributes must be optional.",
 				Type:        schema.TypeList,
@@ -72,6 +129,92 @@ func resourceTfExport() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				ForceNew:    true,
 			},
+			"replace_attributes": {
+				Description: "Rules that rewrite attribute values in the config, e.g. to change the email domain of users when exporting config for another org. Matching values are replaced before the config is written. References to other resources are not replaced.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"attribute": {
+							Description: "Attribute to rewrite of the form {resource_name}.{attribute}, e.g. 'genesyscloud_user.email'. Values of attributes nested in the attribute are also rewritten.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"pattern": {
+							Description:  "Regular expression to match in the attribute values, e.g. '@example\\.com$'.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsValidRegExp,
+						},
+						"replacement": {
+							Description: "Replacement for each match, e.g. '@sandbox.example.com'. Groups in the pattern can be referenced with '$1'.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"layout": {
+				Description:  fmt.Sprintf("Layout of the exported config files (%s | %s | %s). '%s' writes the config of each resource type to its own file, e.g. 'routing_queue.tf.json'. '%s' writes each resource type to a module in its own directory with outputs for the IDs referenced by other types. In both cases the terraform block is written to the main config file.", layoutSingleFile, layoutFilePerType, layoutModulePerType, layoutFilePerType, layoutModulePerType),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      layoutSingleFile,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{layoutSingleFile, layoutFilePerType, layoutModulePerType}, false),
+			},
+			"extract_variables": {
+				Description: fmt.Sprintf("Export attributes that are likely to differ between orgs, such as emails and phone numbers, as variables. The variables are declared in '%s' or '%s', and their current values are written to '%s.json' or '%s'.", getConfigFileName(defaultVariablesFile, false), getConfigFileName(defaultVariablesFile, true), defaultTfVarsFile, defaultTfVarsFile),
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"include_sensitive_variables": {
+				Description: "Write the current values of sensitive variables to the tfvars file. By default sensitive variables are declared but their values must be provided separately.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"drift_report": {
+				Description:   fmt.Sprintf("Compare the resources in the org with the config previously exported to the directory instead of exporting. Added, removed and modified resources are written to '%s.json' and '%s.md', and the exported files are left unchanged.", defaultDriftReportFile, defaultDriftReportFile),
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ForceNew:      true,
+				ConflictsWith: []string{"include_state_file", "import_mode"},
+			},
+			"modified_since": {
+				Description:   "Only read the resources that have been modified since this time, e.g. '2021-08-01T00:00:00Z', and merge them into the config previously exported to the directory. Resources that no longer exist are removed. Resources of types that do not expose a modification date or version, such as user prompts, are always read and counted under 'no_modification_date' in 'export_report.json' when continue_on_error is set.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"include_state_file", "drift_report", "root_resources"},
+			},
+			"verify": {
+				Description:   fmt.Sprintf("Verify the export by loading the exported config and planning each resource against the state read from the org (%s | %s). Resources that would not be a no-op, or whose config is invalid, are written to '%s'. '%s' returns them as a warning and '%s' fails the export.", verifyModeReport, verifyModeFail, defaultVerifyReportFile, verifyModeReport, verifyModeFail),
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.StringInSlice([]string{verifyModeReport, verifyModeFail}, false),
+				ConflictsWith: []string{"drift_report"},
+			},
+			"continue_on_error": {
+				Description: fmt.Sprintf("Skip resources that cannot be read instead of failing the export. Files of resources that cannot be exported, such as prompt audio, are also skipped. The number of resources found, exported and skipped for each type, and the error for each skipped resource or file, are written to '%s'.", defaultExportReportFile),
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"export_as_hcl": {
+				Description: "Export the config as HCL to 'genesyscloud.tf' instead of JSON.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
 		},
 	}
 }
@@ -81,10 +224,22 @@ type resourceInfo struct {
 	Name    string
 	Type    string
 	CtyType cty.Type
+	// ID used to import the resource, including any ResourceMeta.IdPrefix
+	ImportID string
 }
 
 func createTfExport(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	filePath, diagErr := getFilePath(d, defaultTfJSONFile)
+	if clientPool := meta.(*providerMeta).ClientPool; clientPool != nil {
+		defer clientPool.throttler.logStats()
+	}
+
+	exportAsHCL := d.Get("export_as_hcl").(bool)
+	configFile := defaultTfJSONFile
+	if exportAsHCL {
+		configFile = defaultTfHCLFile
+	}
+
+	filePath, diagErr := getFilePath(d, configFile)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -95,8 +250,41 @@ func createTfExport(ctx context.Context, d *schema.ResourceData, meta interface{
 	if resourceTypes, ok := d.GetOk("resource_types"); ok {
 		filter = interfaceListToStrings(resourceTypes.([]interface{}))
 	}
+
+	var includeFilters []string
+	if includeFilterResources, ok := d.GetOk("include_filter_resources"); ok {
+		includeFilters = interfaceListToStrings(includeFilterResources.([]interface{}))
+		filter, diagErr = getFilterResourceTypes(includeFilters)
+		if diagErr != nil {
+			return diagErr
+		}
+	}
+
+	var roots []resourceRef
+	if rootResources, ok := d.GetOk("root_resources"); ok {
+		roots, diagErr = parseRootResources(interfaceListToStrings(rootResources.([]interface{})))
+		if diagErr != nil {
+			return diagErr
+		}
+		// Only the types that can be reached from the roots need to be loaded
+		filter = getDependencyResourceTypes(roots)
+	}
 	exporters := getResourceExporters(filter)
 
+	if diagErr := populateIncludeFilters(exporters, includeFilters); diagErr != nil {
+		return diagErr
+	}
+
+	if excludeFilterResources, ok := d.GetOk("exclude_filter_resources"); ok {
+		if diagErr := populateExcludeFilters(exporters, interfaceListToStrings(excludeFilterResources.([]interface{}))); diagErr != nil {
+			return diagErr
+		}
+	}
+
+	if divisionIDs, ok := d.GetOk("division_ids"); ok {
+		populateDivisionFilters(exporters, interfaceListToStrings(divisionIDs.([]interface{})), d.Get("include_divisionless_types").(bool))
+	}
+
 	if len(exporters) == 0 {
 		return diag.Errorf("No valid resource types to export.")
 	}
@@ -107,73 +295,262 @@ func createTfExport(ctx context.Context, d *schema.ResourceData, meta interface{
 		}
 	}
 
-	diagErr = buildSanitizedResourceMaps(exporters)
+	// Reuse the names from a previous export so the addresses of existing resources do not change
+	namesManifest, diagErr := readNamesManifest(filepath.Dir(filePath))
 	if diagErr != nil {
 		return diagErr
 	}
+	for resType, exporter := range exporters {
+		exporter.ExportedNames = namesManifest[resType]
+	}
+	versionsManifest, diagErr := readVersionsManifest(filepath.Dir(filePath))
+	if diagErr != nil {
+		return diagErr
+	}
+
+	// Errors are recorded in the report instead of failing the export when continuing on errors
+	var report *exportReport
+	if d.Get("continue_on_error").(bool) {
+		report = newExportReport()
+	}
+
+	if replacedAttrs, ok := d.GetOk("replace_attributes"); ok {
+		if diagErr := populateConfigReplaced(exporters, replacedAttrs.([]interface{})); diagErr != nil {
+			return diagErr
+		}
+	}
+
+	diagErr = buildSanitizedResourceMaps(exporters, meta, report)
+	if diagErr != nil {
+		return diagErr
+	}
+
+	foundCounts := make(map[string]int, len(exporters))
+	for resType, exporter := range exporters {
+		foundCounts[resType] = len(exporter.SanitizedResourceMap)
+	}
+
+	// Incremental exports only read the resources modified since the existing export
+	var (
+		modifiedSince       time.Time
+		unmodifiedResources []resourceInfo
+		existingResources   map[string]map[string]map[string]interface{}
+		existingDataSources map[string]map[string]map[string]interface{}
+	)
+	incremental := d.Get("modified_since").(string) != ""
+	if incremental {
+		modifiedSince, _ = time.Parse(time.RFC3339, d.Get("modified_since").(string))
+		existingResources, existingDataSources, diagErr = loadExportedResources(filepath.Dir(filePath), false)
+		if diagErr != nil {
+			return diagErr
+		}
+		unmodifiedResources = markUnmodifiedResources(exporters, existingResources, modifiedSince, versionsManifest, report)
+	}
 
 	includeStateFile := d.Get("include_state_file").(bool)
+	importMode := d.Get("import_mode").(string)
+	// Unmatched references are kept when existing resources will be managed by terraform
+	exportingState := includeStateFile || importMode != ""
 	provider := New(version)()
 
 	// Read the instance data from each exporter
 	var resources []resourceInfo
-	for resType, exporter := range exporters {
-		typeResources, err := getResourcesForType(resType, provider, exporter, meta)
-		if err != nil {
-			return err
+	var warnings diag.Diagnostics
+	if len(roots) > 0 {
+		var unresolved []string
+		resources, unresolved, diagErr = getRootResourceDependencies(roots, provider, exporters, meta, report)
+		if diagErr != nil {
+			return diagErr
+		}
+		if len(unresolved) > 0 {
+			warnings = append(warnings, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Some references could not be followed because the referenced type cannot be exported",
+				Detail:   strings.Join(unresolved, "\n"),
+			})
+		}
+	} else {
+		for resType, exporter := range exporters {
+			typeResources, err := getResourcesForType(resType, provider, exporter, meta, report)
+			if err != nil {
+				return err
+			}
+			resources = append(resources, typeResources...)
 		}
-		resources = append(resources, typeResources...)
 	}
 
-	// Generate the JSON config map
-	resourceTypeJSONMaps := make(map[string]map[string]jsonMap)
-	for _, resource := range resources {
-		jsonResult, diagErr := instanceStateToJSONMap(resource.State, resource.CtyType)
+	// Resources are read concurrently, so sort them to write the same output for the same objects
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].ImportID < resources[j].ImportID
+	})
+
+	configMaps := make([]jsonMap, len(resources))
+	for i, resource := range resources {
+		configMaps[i], diagErr = instanceStateToJSONMap(resource.State, resource.CtyType)
 		if diagErr != nil {
 			return diagErr
 		}
+	}
+
+	// Look up the names of referenced objects that are not exported but can be referenced through a data source
+	dataSources := buildDataSources(resources, configMaps, exporters, getDataSourceNameFuncs(), meta)
+
+	// Generate the JSON config map
+	resourceTypeJSONMaps := make(map[string]map[string]jsonMap)
+	for i, resource := range resources {
+		jsonResult := configMaps[i]
 
 		// Removes zero values and sets proper reference expressions
-		sanitizeConfigMap(resource.Type, jsonResult, "", exporters, includeStateFile)
+		sanitizeConfigMap(resource.Type, jsonResult, "", exporters, dataSources, exportingState)
+		sortSetElements(jsonResult, provider.ResourcesMap[resource.Type].Schema)
 
 		if resourceTypeJSONMaps[resource.Type] == nil {
 			resourceTypeJSONMaps[resource.Type] = make(map[string]jsonMap)
 		}
 
-		if len(resourceTypeJSONMaps[resource.Type][resource.Name]) > 0 {
-			algorithm := fnv.New32()
-			algorithm.Write([]byte(uuid.NewString()))
-			resource.Name = resource.Name + "_" + strconv.FormatUint(uint64(algorithm.Sum32()), 10)
+		resourceTypeJSONMaps[resource.Type][resource.Name] = jsonResult
+	}
+
+	// Resources in the export, including unmodified resources kept from an existing export
+	exportedResources := resources
+	if incremental {
+		if diagErr := mergeExportedResources(resourceTypeJSONMaps, unmodifiedResources, existingResources, existingDataSources, exporters, dataSources); diagErr != nil {
+			return diagErr
 		}
+		exportedResources = append(append([]resourceInfo{}, resources...), unmodifiedResources...)
+	}
 
-		resourceTypeJSONMaps[resource.Type][resource.Name] = jsonResult
+	// Drift reports compare the config read from the org, so files are not exported
+	driftReportOnly := d.Get("drift_report").(bool)
+	if !driftReportOnly {
+		if diagErr := exportResourceFiles(resources, resourceTypeJSONMaps, exporters, filepath.Dir(filePath), meta, report); diagErr != nil {
+			return diagErr
+		}
+	}
+
+	if report != nil {
+		if err := report.write(exporters, foundCounts, exportedResources, filepath.Dir(filePath)); err != nil {
+			return err
+		}
+		if skippedResources, skippedTypes := report.skippedCounts(); skippedResources > 0 || skippedTypes > 0 {
+			warnings = append(warnings, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%d resources and %d resource types could not be read and were skipped", skippedResources, skippedTypes),
+				Detail:   fmt.Sprintf("See %s for the errors", filepath.Join(filepath.Dir(filePath), defaultExportReportFile)),
+			})
+		}
+		if fileErrors := report.fileErrorCount(); fileErrors > 0 {
+			warnings = append(warnings, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The files of %d resources could not be exported. Their config was exported without the files", fileErrors),
+				Detail:   fmt.Sprintf("See %s for the errors", filepath.Join(filepath.Dir(filePath), defaultExportReportFile)),
+			})
+		}
+	}
+
+	if driftReportOnly {
+		driftReport, err := writeDriftReport(resourceTypeJSONMaps, exporters, filepath.Dir(filePath))
+		if err != nil {
+			return err
+		}
+		if driftReport.hasDrift() {
+			warnings = append(warnings, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The org has drifted from the export: %d added, %d removed and %d modified resources", len(driftReport.Added), len(driftReport.Removed), len(driftReport.Modified)),
+			})
+		}
+		d.SetId(filepath.Join(filepath.Dir(filePath), defaultDriftReportFile+".json"))
+		return warnings
+	}
+
+	var variables []*exportVariable
+	if d.Get("extract_variables").(bool) {
+		variables = extractVariables(resourceTypeJSONMaps, exporters, provider)
+		if err := writeVariables(variables, filepath.Dir(filePath), exportAsHCL, d.Get("include_sensitive_variables").(bool), exporters, provider); err != nil {
+			return err
+		}
 	}
 
 	providerSource := sourceForVersion(version)
+	layout := d.Get("layout").(string)
 	if includeStateFile {
-		if err := writeTfState(ctx, resources, d, providerSource); err != nil {
+		if err := writeTfState(resources, d, provider, providerSource, layout); err != nil {
+			return err
+		}
+	}
+	if importMode != "" {
+		if err := writeImports(exportedResources, filepath.Dir(filePath), importMode, layout, exportAsHCL, exporters, provider); err != nil {
 			return err
 		}
 	}
 
-	rootJSONObject := jsonMap{
-		"resource": resourceTypeJSONMaps,
-		"terraform": jsonMap{
-			"required_providers": jsonMap{
-				"genesyscloud": jsonMap{
-					"source":  providerSource,
-					"version": version,
-				},
-			},
-		},
+	if incremental {
+		// Files of types with no remaining resources would otherwise keep deleted resources
+		removeLayoutFiles(filepath.Dir(filePath))
 	}
 
-	if err := writeConfig(rootJSONObject, filePath); err != nil {
-		return err
+	switch layout {
+	case layoutFilePerType:
+		diagErr = writeConfigFilePerType(resourceTypeJSONMaps, filePath, exportAsHCL, exporters, provider, providerSource, version, dataSources)
+	case layoutModulePerType:
+		diagErr = writeConfigModulePerType(resourceTypeJSONMaps, filePath, exportAsHCL, exporters, provider, providerSource, version, variables, dataSources)
+	default:
+		rootJSONObject := jsonMap{
+			"resource":  resourceTypeJSONMaps,
+			"terraform": buildTerraformSettings(providerSource, version),
+		}
+		if len(dataSources) > 0 {
+			rootJSONObject["data"] = dataSources.jsonMaps()
+		}
+		diagErr = writeConfigFile(rootJSONObject, filePath, exportAsHCL, exporters, provider)
+	}
+	if diagErr != nil {
+		return diagErr
+	}
+
+	if diagErr := writeNamesManifest(namesManifest, exporters, filepath.Dir(filePath)); diagErr != nil {
+		return diagErr
+	}
+	if diagErr := writeVersionsManifest(versionsManifest, exporters, filepath.Dir(filePath)); diagErr != nil {
+		return diagErr
+	}
+
+	if verifyMode := d.Get("verify").(string); verifyMode != "" {
+		verifyResult, diagErr := verifyExport(ctx, resources, variables, dataSources, exporters, provider, meta, filepath.Dir(filePath))
+		if diagErr != nil {
+			return diagErr
+		}
+		if len(verifyResult.Failed) > 0 {
+			failed := make([]string, len(verifyResult.Failed))
+			for i, result := range verifyResult.Failed {
+				failed[i] = result.Address
+			}
+			verifyDiag := diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%d exported resources would change if the config was applied. See %s for details", len(failed), filepath.Join(filepath.Dir(filePath), defaultVerifyReportFile)),
+				Detail:   strings.Join(failed, "\n"),
+			}
+			if verifyMode == verifyModeFail {
+				verifyDiag.Severity = diag.Error
+			}
+			warnings = append(warnings, verifyDiag)
+		}
+		if len(verifyResult.Unverified) > 0 {
+			warnings = append(warnings, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%d exported resources could not be verified. See %s for details", len(verifyResult.Unverified), filepath.Join(filepath.Dir(filePath), defaultVerifyReportFile)),
+			})
+		}
+		if warnings.HasError() {
+			return warnings
+		}
 	}
 
 	d.SetId(filePath)
-	return nil
+	return warnings
 }
 
 func sourceForVersion(version string) string {
@@ -196,6 +573,17 @@ func readTfExport(_ context.Context, d *schema.ResourceData, _ interface{}) diag
 }
 
 func deleteTfExport(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	removeExportFile(filepath.Join(d.Get("directory").(string), defaultExportReportFile))
+	removeExportFile(filepath.Join(d.Get("directory").(string), defaultVerifyReportFile))
+
+	if d.Get("drift_report").(bool) {
+		// Only the reports were written, so the existing export is kept
+		directory := d.Get("directory").(string)
+		removeExportFile(filepath.Join(directory, defaultDriftReportFile+".json"))
+		removeExportFile(filepath.Join(directory, defaultDriftReportFile+".md"))
+		return nil
+	}
+
 	configPath := d.Id()
 	if _, err := os.Stat(configPath); err == nil {
 		log.Printf("Deleting export config %s", configPath)
@@ -207,9 +595,31 @@ func deleteTfExport(_ context.Context, d *schema.ResourceData, _ interface{}) di
 		log.Printf("Deleting export state %s", stateFile)
 		os.Remove(stateFile)
 	}
+
+	// Remove any files downloaded by the exporters
+	os.RemoveAll(filepath.Join(d.Get("directory").(string), promptsExportDir))
+
+	if d.Get("extract_variables").(bool) {
+		removeVariableFiles(d.Get("directory").(string))
+	}
+
+	if d.Get("import_mode").(string) != "" {
+		removeImportFiles(d.Get("directory").(string))
+	}
+
+	if d.Get("layout").(string) != layoutSingleFile {
+		removeLayoutFiles(d.Get("directory").(string))
+	}
 	return nil
 }
 
+func removeExportFile(path string) {
+	if _, err := os.Stat(path); err == nil {
+		log.Printf("Deleting export file %s", path)
+		os.Remove(path)
+	}
+}
+
 func getFilePath(d *schema.ResourceData, filename string) (string, diag.Diagnostics) {
 	directory := d.Get("directory").(string)
 	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
@@ -223,7 +633,8 @@ func getFilePath(d *schema.ResourceData, filename string) (string, diag.Diagnost
 	return path, nil
 }
 
-func buildSanitizedResourceMaps(exporters map[string]*ResourceExporter) diag.Diagnostics {
+// Loads the resource map of each exporter. If report is set, types that fail to load are recorded and left unloaded
+func buildSanitizedResourceMaps(exporters map[string]*ResourceExporter, meta interface{}, report *exportReport) diag.Diagnostics {
 	errorChan := make(chan diag.Diagnostics)
 	wgDone := make(chan bool)
 
@@ -237,7 +648,11 @@ func buildSanitizedResourceMaps(exporters map[string]*ResourceExporter) diag.Dia
 		go func(name string, exporter *ResourceExporter) {
 			defer wg.Done()
 			log.Printf("Getting all resources for type %s", name)
-			err := exporter.loadSanitizedResourceMap(ctx)
+			err := exporter.loadSanitizedResourceMap(ctx, meta)
+			if err != nil && report != nil {
+				report.addTypeError(name, err)
+				return
+			}
 			if err != nil {
 				select {
 				case <-ctx.Done():
@@ -264,8 +679,18 @@ func buildSanitizedResourceMaps(exporters map[string]*ResourceExporter) diag.Dia
 	}
 }
 
-func getResourcesForType(resType string, provider *schema.Provider, exporter *ResourceExporter, meta interface{}) ([]resourceInfo, diag.Diagnostics) {
-	lenResources := len(exporter.SanitizedResourceMap)
+// Reads the state of each resource in the exporter's resource map. If report is set, resources that fail to be read
+// are recorded and removed from the map instead of failing the export.
+func getResourcesForType(resType string, provider *schema.Provider, exporter *ResourceExporter, meta interface{}, report *exportReport) ([]resourceInfo, diag.Diagnostics) {
+	// Unmodified resources are kept from the existing export
+	readResources := make(ResourceIDMetaMap, len(exporter.SanitizedResourceMap))
+	for id, resMeta := range exporter.SanitizedResourceMap {
+		if !exporter.UnmodifiedIDs[id] {
+			readResources[id] = resMeta
+		}
+	}
+
+	lenResources := len(readResources)
 	errorChan := make(chan diag.Diagnostics, lenResources)
 	resourceChan := make(chan resourceInfo, lenResources)
 	removeChan := make(chan string, lenResources)
@@ -283,13 +708,18 @@ func getResourcesForType(resType string, provider *schema.Provider, exporter *Re
 
 	var wg sync.WaitGroup
 	wg.Add(lenResources)
-	for id, resMeta := range exporter.SanitizedResourceMap {
+	for id, resMeta := range readResources {
 		go func(id string, resMeta *ResourceMeta) {
 			defer wg.Done()
 
 			// This calls into the resource's ReadContext method which
 			// will block until it can acquire a pooled client config object.
 			instanceState, err := getResourceState(ctx, resource, id, resMeta, meta)
+			if err != nil && report != nil {
+				report.addResourceError(resType, id, err)
+				removeChan <- id // Skip references to the resource
+				return
+			}
 			if err != nil {
 				errorChan <- diag.Errorf("Failed to get state for %s instance %s: %v", resType, id, err)
 				cancel() // Stop other requests
@@ -303,10 +733,11 @@ func getResourcesForType(resType string, provider *schema.Provider, exporter *Re
 			}
 
 			resourceChan <- resourceInfo{
-				State:   instanceState,
-				Name:    resMeta.Name,
-				Type:    resType,
-				CtyType: ctyType,
+				State:    instanceState,
+				Name:     resMeta.Name,
+				Type:     resType,
+				CtyType:  ctyType,
+				ImportID: resMeta.IdPrefix + id,
 			}
 		}(id, resMeta)
 	}
@@ -384,20 +815,16 @@ func writeToFile(bytes []byte, path string) diag.Diagnostics {
 	return nil
 }
 
-func writeTfState(ctx context.Context, resources []resourceInfo, d *schema.ResourceData, providerSource string) diag.Diagnostics {
+func writeTfState(resources []resourceInfo, d *schema.ResourceData, provider *schema.Provider, providerSource string, layout string) diag.Diagnostics {
 	stateFilePath, diagErr := getFilePath(d, defaultTfStateFile)
 	if diagErr != nil {
 		return diagErr
 	}
 
-	tfstate := terraform.NewState()
-	for _, resource := range resources {
-		resourceState := &terraform.ResourceState{
-			Type:     resource.Type,
-			Primary:  resource.State,
-			Provider: "provider.genesyscloud",
-		}
-		tfstate.RootModule().Resources[resource.Type+"."+resource.Name] = resourceState
+	lineage, serial := getStateLineageAndSerial(stateFilePath)
+	tfstate, diagErr := buildTfStateV4(resources, provider, providerSource, layout, lineage, serial)
+	if diagErr != nil {
+		return diagErr
 	}
 
 	data, err := json.MarshalIndent(tfstate, "", "  ")
@@ -406,54 +833,7 @@ func writeTfState(ctx context.Context, resources []resourceInfo, d *schema.Resou
 	}
 
 	log.Printf("Writing export state file to %s", stateFilePath)
-	if err := writeToFile(data, stateFilePath); err != nil {
-		return err
-	}
-
-	// This outputs terraform state v3, and there is currently no public lib to generate v4 which is required for terraform 0.13+.
-	// However, the state can be upgraded automatically by calling the terraform CLI. If this fails, just print a warning indicating
-	// that the state likely needs to be upgraded manually.
-	cliError := `Failed to run the terraform CLI to upgrade the generated state file. 
-	The generated tfstate file will need to be upgraded manually by running the 
-	following in the state file's directory:
-	'terraform state replace-provider registry.terraform.io/-/genesyscloud registry.terraform.io/mypurecloud/genesyscloud'`
-
-	tfpath, err := exec.LookPath("terraform")
-	if err != nil {
-		log.Println(cliError)
-		return nil
-	}
-
-	// exec.CommandContext does not auto-resolve symlinks
-	fileInfo, err := os.Lstat(tfpath)
-	if err != nil {
-		log.Println(cliError)
-		return nil
-	}
-	if fileInfo.Mode()&os.ModeSymlink != 0 {
-		tfpath, err = os.Readlink(tfpath)
-		if err != nil {
-			log.Println(cliError)
-			return nil
-		}
-	}
-
-	cmd := exec.CommandContext(ctx, tfpath)
-	cmd.Args = append(cmd.Args, []string{
-		"state",
-		"replace-provider",
-		"-auto-approve",
-		"-state=" + stateFilePath,
-		"registry.terraform.io/-/genesyscloud",
-		providerSource,
-	}...)
-
-	log.Printf("Running 'terraform state replace-provider' on %s", stateFilePath)
-	if err := cmd.Run(); err != nil {
-		log.Println(cliError)
-		return nil
-	}
-	return nil
+	return writeToFile(data, stateFilePath)
 }
 
 func writeConfig(jsonMap map[string]interface{}, path string) diag.Diagnostics {
@@ -479,6 +859,7 @@ func sanitizeConfigMap(
 	configMap map[string]interface{},
 	prevAttr string,
 	exporters map[string]*ResourceExporter,
+	dataSources exportedDataSources,
 	exportingState bool) bool {
 
 	exporter := exporters[resourceType]
@@ -506,12 +887,12 @@ func sanitizeConfigMap(
 		case map[string]interface{}:
 			// Maps are sanitized in-place
 			currMap := val.(map[string]interface{})
-			if !sanitizeConfigMap(resourceType, val.(map[string]interface{}), currAttr, exporters, exportingState) || len(currMap) == 0 {
+			if !sanitizeConfigMap(resourceType, val.(map[string]interface{}), currAttr, exporters, dataSources, exportingState) || len(currMap) == 0 {
 				// Remove empty maps or maps indicating they should be removed
 				configMap[key] = nil
 			}
 		case []interface{}:
-			if arr := sanitizeConfigArray(resourceType, val.([]interface{}), currAttr, exporters, exportingState); len(arr) > 0 {
+			if arr := sanitizeConfigArray(resourceType, val.([]interface{}), currAttr, exporters, dataSources, exportingState); len(arr) > 0 {
 				configMap[key] = arr
 			} else {
 				// Remove empty arrays
@@ -525,9 +906,9 @@ func sanitizeConfigMap(
 				refSettings = exporter.getRefAttrSettings(wildcardAttr)
 			}
 			if refSettings != nil {
-				configMap[key] = resolveReference(refSettings, val.(string), exporters, exportingState)
+				configMap[key] = resolveReference(refSettings, val.(string), exporters, dataSources, exportingState)
 			} else {
-				configMap[key] = escapeString(val.(string))
+				configMap[key] = escapeString(exporter.replaceAttributeValue(currAttr, val.(string)))
 			}
 		}
 
@@ -578,6 +959,7 @@ func sanitizeConfigArray(
 	anArray []interface{},
 	currAttr string,
 	exporters map[string]*ResourceExporter,
+	dataSources exportedDataSources,
 	exportingState bool) []interface{} {
 	exporter := exporters[resourceType]
 	result := []interface{}{}
@@ -586,22 +968,22 @@ func sanitizeConfigArray(
 		case map[string]interface{}:
 			// Only include in the result if sanitizeConfigMap returns true and the map is not empty
 			currMap := val.(map[string]interface{})
-			if sanitizeConfigMap(resourceType, currMap, currAttr, exporters, exportingState) && len(currMap) > 0 {
+			if sanitizeConfigMap(resourceType, currMap, currAttr, exporters, dataSources, exportingState) && len(currMap) > 0 {
 				result = append(result, val)
 			}
 		case []interface{}:
-			if arr := sanitizeConfigArray(resourceType, val.([]interface{}), currAttr, exporters, exportingState); len(arr) > 0 {
+			if arr := sanitizeConfigArray(resourceType, val.([]interface{}), currAttr, exporters, dataSources, exportingState); len(arr) > 0 {
 				result = append(result, arr)
 			}
 		case string:
 			// Check if we are on a reference attribute and update value in array
 			if refSettings := exporter.getRefAttrSettings(currAttr); refSettings != nil {
-				referenceVal := resolveReference(refSettings, val.(string), exporters, exportingState)
+				referenceVal := resolveReference(refSettings, val.(string), exporters, dataSources, exportingState)
 				if referenceVal != "" {
 					result = append(result, referenceVal)
 				}
 			} else {
-				result = append(result, escapeString(val.(string)))
+				result = append(result, escapeString(exporter.replaceAttributeValue(currAttr, val.(string))))
 			}
 		default:
 			result = append(result, val)
@@ -610,7 +992,7 @@ func sanitizeConfigArray(
 	return result
 }
 
-func resolveReference(refSettings *RefAttrSettings, refID string, exporters map[string]*ResourceExporter, exportingState bool) string {
+func resolveReference(refSettings *RefAttrSettings, refID string, exporters map[string]*ResourceExporter, dataSources exportedDataSources, exportingState bool) string {
 	if stringInSlice(refID, refSettings.AltValues) {
 		// This is not actually a reference to another object. Keep the value
 		return refID
@@ -625,6 +1007,13 @@ func resolveReference(refSettings *RefAttrSettings, refID string, exporters map[
 		}
 	}
 
+	if refSettings.DataSource != "" {
+		// Look up the referenced object by name if it is not being exported
+		if dataSourceRef := dataSources.getReference(refSettings.DataSource, refID); dataSourceRef != "" {
+			return dataSourceRef
+		}
+	}
+
 	if exportingState {
 		// Don't remove unmatched IDs when exporting state. This will keep existing config in an org
 		return refID
@@ -655,3 +1044,109 @@ func populateConfigExcluded(exporters map[string]*ResourceExporter, configExclud
 	}
 	return nil
 }
+
+func populateConfigReplaced(exporters map[string]*ResourceExporter, configReplaced []interface{}) diag.Diagnostics {
+	for _, configReplacement := range configReplaced {
+		replacementMap := configReplacement.(map[string]interface{})
+		replaced := replacementMap["attribute"].(string)
+		resourceIdx := strings.Index(replaced, ".")
+		if resourceIdx == -1 || resourceIdx == len(replaced)-1 {
+			return diag.Errorf("replace_attributes value %s does not contain an attribute", replaced)
+		}
+
+		resourceName := replaced[:resourceIdx]
+		exporter := exporters[resourceName]
+		if exporter == nil {
+			return diag.Errorf("Resource %s in replace_attributes is not being exported.", resourceName)
+		}
+		pattern, err := regexp.Compile(replacementMap["pattern"].(string))
+		if err != nil {
+			return diag.Errorf("Invalid pattern in replace_attributes for %s: %v", replaced, err)
+		}
+		replacedAttr := replaced[resourceIdx+1:]
+		exporter.ReplacedAttributes = append(exporter.ReplacedAttributes, &AttributeReplacement{
+			Attribute:   replacedAttr,
+			Pattern:     pattern,
+			Replacement: replacementMap["replacement"].(string),
+		})
+		log.Printf("Replacing values of attribute %s on %s resources.", replacedAttr, resourceName)
+	}
+	return nil
+}
+
+func getFilterResourceTypes(filters []string) ([]string, diag.Diagnostics) {
+	var resTypes []string
+	for _, filter := range filters {
+		resType, _, err := parseResourceFilter(filter)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if !stringInSlice(resType, resTypes) {
+			resTypes = append(resTypes, resType)
+		}
+	}
+	return resTypes, nil
+}
+
+func populateIncludeFilters(exporters map[string]*ResourceExporter, includeFilters []string) diag.Diagnostics {
+	// Types included without an expression export all resources of that type
+	var unfilteredTypes []string
+	for _, filter := range includeFilters {
+		resType, nameFilter, err := parseResourceFilter(filter)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if nameFilter == nil {
+			unfilteredTypes = append(unfilteredTypes, resType)
+		}
+	}
+
+	for _, filter := range includeFilters {
+		resType, nameFilter, _ := parseResourceFilter(filter)
+		exporter := exporters[resType]
+		if nameFilter == nil || exporter == nil || stringInSlice(resType, unfilteredTypes) {
+			continue
+		}
+		exporter.IncludeNameFilters = append(exporter.IncludeNameFilters, nameFilter)
+		log.Printf("Including %s resources with names matching %s.", resType, nameFilter)
+	}
+	return nil
+}
+
+func populateDivisionFilters(exporters map[string]*ResourceExporter, divisionIDs []string, includeDivisionless bool) {
+	for resType, exporter := range exporters {
+		if !exporter.DivisionAware {
+			if !includeDivisionless {
+				delete(exporters, resType)
+				log.Printf("Excluding %s resources as the type is not division-aware.", resType)
+			}
+			continue
+		}
+		exporter.DivisionIDs = divisionIDs
+		log.Printf("Filtering %s resources to divisions %s.", resType, strings.Join(divisionIDs, ", "))
+	}
+}
+
+func populateExcludeFilters(exporters map[string]*ResourceExporter, excludeFilters []string) diag.Diagnostics {
+	for _, filter := range excludeFilters {
+		resType, nameFilter, err := parseResourceFilter(filter)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		exporter := exporters[resType]
+		if exporter == nil {
+			log.Printf("Resource %s in exclude_filter_resources is not being exported.", resType)
+			continue
+		}
+
+		if nameFilter == nil {
+			delete(exporters, resType)
+			log.Printf("Excluding all %s resources.", resType)
+			continue
+		}
+		exporter.ExcludeNameFilters = append(exporter.ExcludeNameFilters, nameFilter)
+		log.Printf("Excluding %s resources with names matching %s.", resType, nameFilter)
+	}
+	return nil
+}
@@ -1,16 +1,27 @@
 package genesyscloud
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
 	"gonum.org/v1/gonum/graph/simple"
 	"gonum.org/v1/gonum/graph/topo"
 )
@@ -112,6 +123,1371 @@ func TestForExportCycles(t *testing.T) {
 	}
 }
 
+func TestExportHCLConfig(t *testing.T) {
+	exporters := getResourceExporters([]string{"genesyscloud_architect_datatable_row", "genesyscloud_routing_queue", "genesyscloud_user"})
+	resourceTypeJSONMaps := map[string]map[string]jsonMap{
+		"genesyscloud_architect_datatable_row": {
+			"row_1": jsonMap{
+				"datatable_id":    "${genesyscloud_architect_datatable.table_1.id}",
+				"key_value":       "key",
+				"properties_json": `{"count":5,"text":"$${literal}"}`,
+			},
+		},
+		"genesyscloud_routing_queue": {
+			"queue_1": jsonMap{
+				"name": "Queue 1",
+				"bullseye_rings": []interface{}{
+					map[string]interface{}{
+						"expansion_timeout_seconds": float64(10),
+						"skills_to_remove":          []interface{}{"${genesyscloud_routing_skill.skill_1.id}"},
+					},
+				},
+			},
+		},
+		"genesyscloud_user": {
+			"user_1": jsonMap{
+				"email":   "user@example.com",
+				"name":    "$${not_a_ref}",
+				"manager": nil,
+				"routing_skills": []interface{}{
+					map[string]interface{}{
+						"skill_id":    "${genesyscloud_routing_skill.skill_1.id}",
+						"proficiency": nil,
+					},
+				},
+			},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), defaultTfHCLFile)
+	rootJSONObject := jsonMap{
+		"resource":  resourceTypeJSONMaps,
+		"terraform": buildTerraformSettings("genesys.com/mypurecloud/genesyscloud", "0.1.0"),
+	}
+	if err := writeHCLConfig(rootJSONObject, exporters, New("0.1.0")(), path); err != nil {
+		t.Fatalf("Failed to write HCL config: %v", err)
+	}
+
+	hclBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := hclsyntax.ParseConfig(hclBytes, path, hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("Exported HCL is not valid: %v\n%s", diags, hclBytes)
+	}
+
+	config := string(hclBytes)
+	for _, expected := range []string{
+		`resource "genesyscloud_user" "user_1" {`,
+		"datatable_id = genesyscloud_architect_datatable.table_1.id",
+		"properties_json = jsonencode({",
+		`text  = "$${literal}"`,
+		"bullseye_rings {",
+		"skills_to_remove          = [genesyscloud_routing_skill.skill_1.id]",
+		`name  = "$${not_a_ref}"`,
+		"routing_skills = [",
+		"proficiency = null",
+		"skill_id    = genesyscloud_routing_skill.skill_1.id",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Exported HCL does not contain '%s':\n%s", expected, config)
+		}
+	}
+	if strings.Contains(config, "manager") {
+		t.Errorf("Exported HCL should not contain null resource attributes:\n%s", config)
+	}
+}
+
+func TestExportTfStateV4(t *testing.T) {
+	provider := New("1.0.0")()
+	newResourceInfo := func(resType string, name string, attributes map[string]string) resourceInfo {
+		return resourceInfo{
+			State:   &terraform.InstanceState{ID: attributes["id"], Attributes: attributes},
+			Name:    name,
+			Type:    resType,
+			CtyType: provider.ResourcesMap[resType].CoreConfigSchema().ImpliedType(),
+		}
+	}
+	resources := []resourceInfo{
+		newResourceInfo("genesyscloud_routing_wrapupcode", "code_1", map[string]string{"id": "code-id", "name": "Code 1"}),
+		newResourceInfo("genesyscloud_routing_skill", "skill_1", map[string]string{"id": "skill-id", "name": "Skill 1"}),
+	}
+
+	tfstate, diagErr := buildTfStateV4(resources, provider, sourceForVersion("1.0.0"), layoutSingleFile, "00000000-0000-0000-0000-000000000000", 3)
+	if diagErr != nil {
+		t.Fatalf("Failed to build state: %v", diagErr)
+	}
+	stateJSON, err := json.Marshal(tfstate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJSON := `{
+		"version": 4,
+		"terraform_version": "0.13.0",
+		"serial": 3,
+		"lineage": "00000000-0000-0000-0000-000000000000",
+		"outputs": {},
+		"resources": [
+			{
+				"mode": "managed",
+				"type": "genesyscloud_routing_skill",
+				"name": "skill_1",
+				"provider": "provider[\"registry.terraform.io/mypurecloud/genesyscloud\"]",
+				"instances": [
+					{
+						"schema_version": 1,
+						"attributes": {"id": "skill-id", "name": "Skill 1"},
+						"sensitive_attributes": []
+					}
+				]
+			},
+			{
+				"mode": "managed",
+				"type": "genesyscloud_routing_wrapupcode",
+				"name": "code_1",
+				"provider": "provider[\"registry.terraform.io/mypurecloud/genesyscloud\"]",
+				"instances": [
+					{
+						"schema_version": 1,
+						"attributes": {"id": "code-id", "name": "Code 1"},
+						"sensitive_attributes": []
+					}
+				]
+			}
+		]
+	}`
+	if !jsonBytesEqual(stateJSON, []byte(expectedJSON)) {
+		t.Errorf("State does not match expected v4 state:\n%s", stateJSON)
+	}
+
+	// Writing the state again should keep the lineage and increment the serial
+	d := resourceTfExport().TestResourceData()
+	d.Set("directory", t.TempDir())
+	statePath, _ := getFilePath(d, defaultTfStateFile)
+	for i := 0; i < 2; i++ {
+		if diagErr := writeTfState(resources, d, provider, sourceForVersion("1.0.0"), layoutSingleFile); diagErr != nil {
+			t.Fatalf("Failed to write state: %v", diagErr)
+		}
+	}
+	stateBytes, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written tfStateV4
+	if err := json.Unmarshal(stateBytes, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written.Serial != 2 || written.Lineage == "" {
+		t.Errorf("Expected serial 2 and a lineage after writing state twice. Got serial %d and lineage '%s'", written.Serial, written.Lineage)
+	}
+}
+
+func TestExportResourceFilters(t *testing.T) {
+	resTypes := []string{"genesyscloud_routing_queue", "genesyscloud_user"}
+	resources := ResourceIDMetaMap{
+		"1": {Name: "Sales_East"},
+		"2": {Name: "Sales West"},
+		"3": {Name: "Support"},
+		"4": {Name: "test_Sales"},
+	}
+
+	exporters := newTestExporters(resTypes, resources)
+	if diagErr := populateIncludeFilters(exporters, []string{"genesyscloud_routing_queue::^Sales", "genesyscloud_user"}); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if diagErr := populateExcludeFilters(exporters, []string{"genesyscloud_routing_queue::West$", "genesyscloud_user::^test_"}); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if diagErr := buildSanitizedResourceMaps(exporters, nil, nil); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+
+	expectedIDs := map[string][]string{
+		"genesyscloud_routing_queue": {"1"},
+		"genesyscloud_user":          {"1", "2", "3"},
+	}
+	for resType, ids := range expectedIDs {
+		resourceMap := exporters[resType].SanitizedResourceMap
+		if len(resourceMap) != len(ids) {
+			t.Errorf("Expected %d %s resources after filtering. Found %d", len(ids), resType, len(resourceMap))
+		}
+		for _, id := range ids {
+			if resourceMap[id] == nil {
+				t.Errorf("Expected %s resource %s to be included", resType, id)
+			}
+		}
+	}
+
+	// Excluding a type without an expression removes its exporter
+	exporters = newTestExporters(resTypes, resources)
+	if diagErr := populateExcludeFilters(exporters, []string{"genesyscloud_user"}); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if exporters["genesyscloud_user"] != nil {
+		t.Error("Expected genesyscloud_user exporter to be removed")
+	}
+
+	if _, _, err := parseResourceFilter("genesyscloud_user::("); err == nil {
+		t.Error("Expected an error for an invalid name expression")
+	}
+}
+
+func TestExportDivisionFilter(t *testing.T) {
+	resTypes := []string{"genesyscloud_routing_queue", "genesyscloud_routing_skill"}
+	resources := ResourceIDMetaMap{
+		"1": {Name: "Sales", DivisionID: "div-1"},
+		"2": {Name: "Support", DivisionID: "div-2"},
+		"3": {Name: "Billing", DivisionID: "div-3"},
+	}
+
+	// Types that are not division-aware are skipped by default
+	exporters := newTestExporters(resTypes, resources)
+	populateDivisionFilters(exporters, []string{"div-1", "div-2"}, false)
+	if exporters["genesyscloud_routing_skill"] != nil {
+		t.Error("Expected genesyscloud_routing_skill exporter to be removed")
+	}
+	if diagErr := buildSanitizedResourceMaps(exporters, nil, nil); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	queueMap := exporters["genesyscloud_routing_queue"].SanitizedResourceMap
+	if len(queueMap) != 2 || queueMap["1"] == nil || queueMap["2"] == nil {
+		t.Errorf("Expected queues in div-1 and div-2 to be exported. Found %v", queueMap)
+	}
+
+	// Types that are not division-aware are exported unfiltered when included
+	exporters = newTestExporters(resTypes, resources)
+	populateDivisionFilters(exporters, []string{"div-3"}, true)
+	if diagErr := buildSanitizedResourceMaps(exporters, nil, nil); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if len(exporters["genesyscloud_routing_skill"].SanitizedResourceMap) != 3 {
+		t.Errorf("Expected all skills to be exported. Found %d", len(exporters["genesyscloud_routing_skill"].SanitizedResourceMap))
+	}
+	queueMap = exporters["genesyscloud_routing_queue"].SanitizedResourceMap
+	if len(queueMap) != 1 || queueMap["3"] == nil {
+		t.Errorf("Expected only the queue in div-3 to be exported. Found %v", queueMap)
+	}
+}
+
+func TestExportModulePerTypeLayout(t *testing.T) {
+	exporters := getResourceExporters([]string{"genesyscloud_routing_queue", "genesyscloud_routing_skill"})
+	resourceTypeJSONMaps := map[string]map[string]jsonMap{
+		"genesyscloud_routing_queue": {
+			"queue_1": jsonMap{
+				"name": "Queue 1",
+				"bullseye_rings": []interface{}{
+					map[string]interface{}{
+						"expansion_timeout_seconds": float64(10),
+						"skills_to_remove":          []interface{}{"${genesyscloud_routing_skill.skill_1.id}"},
+					},
+				},
+			},
+		},
+		"genesyscloud_routing_skill": {
+			"skill_1": jsonMap{"name": "Skill 1"},
+			"skill_2": jsonMap{"name": "Skill 2"},
+		},
+	}
+
+	directory := t.TempDir()
+	rootPath := filepath.Join(directory, defaultTfHCLFile)
+	if err := writeConfigModulePerType(resourceTypeJSONMaps, rootPath, true, exporters, New("0.1.0")(), "genesys.com/mypurecloud/genesyscloud", "0.1.0", nil, nil); err != nil {
+		t.Fatalf("Failed to write modules: %v", err)
+	}
+
+	expectedFiles := map[string][]string{
+		rootPath: {
+			`module "routing_queue" {`,
+			"routing_skill_skill_1_id = module.routing_skill.skill_1_id",
+			`source                   = "./routing_queue"`,
+			`version = "0.1.0"`,
+		},
+		filepath.Join(directory, "routing_queue", "main.tf"): {
+			`variable "routing_skill_skill_1_id" {`,
+			"type = string",
+			"skills_to_remove          = [var.routing_skill_skill_1_id]",
+		},
+		filepath.Join(directory, "routing_skill", "main.tf"): {
+			`output "skill_1_id" {`,
+			"value = genesyscloud_routing_skill.skill_1.id",
+		},
+	}
+	for path, expectedContent := range expectedFiles {
+		hclBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, diags := hclsyntax.ParseConfig(hclBytes, path, hcl.InitialPos); diags.HasErrors() {
+			t.Fatalf("Exported HCL is not valid: %v\n%s", diags, hclBytes)
+		}
+		for _, expected := range expectedContent {
+			if !strings.Contains(string(hclBytes), expected) {
+				t.Errorf("%s does not contain '%s':\n%s", path, expected, hclBytes)
+			}
+		}
+		if strings.Contains(string(hclBytes), "skill_2_id") {
+			t.Errorf("%s should only output referenced IDs:\n%s", path, hclBytes)
+		}
+	}
+}
+
+func TestExportRootResourceDependencies(t *testing.T) {
+	roots, diagErr := parseRootResources([]string{"genesyscloud_routing_queue::queue-1"})
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if _, diagErr := parseRootResources([]string{"genesyscloud_routing_queue"}); diagErr == nil {
+		t.Error("Expected an error for a root resource without an ID")
+	}
+
+	resTypes := getDependencyResourceTypes(roots)
+	for _, expected := range []string{"genesyscloud_routing_queue", "genesyscloud_routing_skill", "genesyscloud_user", "genesyscloud_auth_division"} {
+		if !stringInSlice(expected, resTypes) {
+			t.Errorf("Expected dependency types %v to contain %s", resTypes, expected)
+		}
+	}
+	if stringInSlice("genesyscloud_architect_ivr", resTypes) {
+		t.Errorf("Dependency types %v should not contain unreferenced types", resTypes)
+	}
+
+	exporter := routingQueueExporter()
+	refs := collectReferences(exporter, map[string]interface{}{
+		"id":                "queue-1",
+		"name":              "Queue 1",
+		"division_id":       "division-1",
+		"queue_flow_id":     "flow-1",
+		"whisper_prompt_id": "prompt-1",
+		"members": []interface{}{
+			map[string]interface{}{"user_id": "user-1", "ring_num": float64(1)},
+		},
+		"wrapup_codes": []interface{}{"code-1", "code-2"},
+	}, "")
+
+	expectedRefs := map[string]string{
+		"division_id":       "genesyscloud_auth_division::division-1",
+		"queue_flow_id":     "genesyscloud_flow::flow-1",
+		"whisper_prompt_id": "::prompt-1",
+		"members.user_id":   "genesyscloud_user::user-1",
+		"wrapup_codes":      "genesyscloud_routing_wrapupcode::code-1,genesyscloud_routing_wrapupcode::code-2",
+	}
+	foundRefs := make(map[string][]string)
+	for _, ref := range refs {
+		foundRefs[ref.Attribute] = append(foundRefs[ref.Attribute], ref.String())
+	}
+	if len(foundRefs) != len(expectedRefs) {
+		t.Errorf("Expected references from %d attributes. Found %v", len(expectedRefs), foundRefs)
+	}
+	for attr, expected := range expectedRefs {
+		if found := strings.Join(foundRefs[attr], ","); found != expected {
+			t.Errorf("Expected %s to reference %s. Found %s", attr, expected, found)
+		}
+	}
+}
+
+func TestExportImports(t *testing.T) {
+	resources := []resourceInfo{
+		{Type: "genesyscloud_routing_skill", Name: "skill_1", ImportID: "skill-id"},
+		{Type: "genesyscloud_routing_email_route", Name: "route_1", ImportID: "domain-id/route-id"},
+	}
+	provider := New("0.1.0")()
+	directory := t.TempDir()
+
+	if err := writeImports(resources, directory, importModeBlocks, layoutModulePerType, true, nil, provider); err != nil {
+		t.Fatalf("Failed to write import blocks: %v", err)
+	}
+	importsPath := filepath.Join(directory, "imports.tf")
+	hclBytes, err := ioutil.ReadFile(importsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := hclsyntax.ParseConfig(hclBytes, importsPath, hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("Exported HCL is not valid: %v\n%s", diags, hclBytes)
+	}
+	expectedBlocks := `import {
+  to = module.routing_email_route.genesyscloud_routing_email_route.route_1
+  id = "domain-id/route-id"
+}
+
+import {
+  to = module.routing_skill.genesyscloud_routing_skill.skill_1
+  id = "skill-id"
+}
+`
+	if strings.TrimSpace(string(hclBytes)) != strings.TrimSpace(expectedBlocks) {
+		t.Errorf("Unexpected import blocks:\n%s", hclBytes)
+	}
+
+	if err := writeImports(resources, directory, importModeScript, layoutSingleFile, false, nil, provider); err != nil {
+		t.Fatalf("Failed to write import script: %v", err)
+	}
+	scriptBytes, err := ioutil.ReadFile(filepath.Join(directory, "import.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"terraform import 'genesyscloud_routing_email_route.route_1' 'domain-id/route-id'\n",
+		"terraform import 'genesyscloud_routing_skill.skill_1' 'skill-id'\n",
+	} {
+		if !strings.Contains(string(scriptBytes), expected) {
+			t.Errorf("Import script does not contain '%s':\n%s", expected, scriptBytes)
+		}
+	}
+
+	if quoted := shellQuote("it's"); quoted != `'it'\''s'` {
+		t.Errorf("Unexpected quoted value %s", quoted)
+	}
+}
+
+func TestExportVariables(t *testing.T) {
+	exporters := getResourceExporters([]string{"genesyscloud_user", "genesyscloud_integration_credential"})
+	resourceTypeJSONMaps := map[string]map[string]jsonMap{
+		"genesyscloud_user": {
+			"user_1": jsonMap{"name": "User 1", "email": "user1@example.com"},
+		},
+		"genesyscloud_integration_credential": {
+			"cred_1": jsonMap{"name": "Cred 1", "fields": map[string]interface{}{"key": "$${secret}"}},
+		},
+	}
+	provider := New("0.1.0")()
+
+	variables := extractVariables(resourceTypeJSONMaps, exporters, provider)
+	if len(variables) != 2 {
+		t.Fatalf("Expected 2 variables. Found %d", len(variables))
+	}
+	if email := resourceTypeJSONMaps["genesyscloud_user"]["user_1"]["email"]; email != "${var.user_user_1_email}" {
+		t.Errorf("Expected email to reference a variable. Found %v", email)
+	}
+	if fields := resourceTypeJSONMaps["genesyscloud_integration_credential"]["cred_1"]["fields"]; fields != "${var.integration_credential_cred_1_fields}" {
+		t.Errorf("Expected fields to reference a variable. Found %v", fields)
+	}
+	// Variable values are literals, so they are not escaped like the config
+	if value := variables[0].Value; !reflect.DeepEqual(value, map[string]interface{}{"key": "${secret}"}) {
+		t.Errorf("Expected the unescaped value of fields. Found %v", value)
+	}
+
+	directory := t.TempDir()
+	if err := writeVariables(variables, directory, false, false, exporters, provider); err != nil {
+		t.Fatalf("Failed to write variables: %v", err)
+	}
+
+	var declarations map[string]map[string]map[string]interface{}
+	readTestJSONFile(t, filepath.Join(directory, "variables.tf.json"), &declarations)
+	expectedDeclarations := map[string]map[string]interface{}{
+		"integration_credential_cred_1_fields": {
+			"type":        "map(string)",
+			"description": "fields of genesyscloud_integration_credential.cred_1",
+			"sensitive":   true,
+		},
+		"user_user_1_email": {
+			"type":        "string",
+			"description": "email of genesyscloud_user.user_1",
+		},
+	}
+	if !reflect.DeepEqual(declarations["variable"], expectedDeclarations) {
+		t.Errorf("Unexpected variable declarations: %v", declarations)
+	}
+
+	// Sensitive values are left out of the tfvars file by default
+	var values map[string]interface{}
+	readTestJSONFile(t, filepath.Join(directory, "terraform.tfvars.json"), &values)
+	if !reflect.DeepEqual(values, map[string]interface{}{"user_user_1_email": "user1@example.com"}) {
+		t.Errorf("Unexpected variable values: %v", values)
+	}
+
+	if err := writeVariables(variables, directory, true, true, exporters, provider); err != nil {
+		t.Fatalf("Failed to write variables: %v", err)
+	}
+	for path, expected := range map[string]string{
+		filepath.Join(directory, "variables.tf"):     "type        = map(string)",
+		filepath.Join(directory, "terraform.tfvars"): `key = "$${secret}"`,
+	} {
+		hclBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, diags := hclsyntax.ParseConfig(hclBytes, path, hcl.InitialPos); diags.HasErrors() {
+			t.Fatalf("Exported HCL is not valid: %v\n%s", diags, hclBytes)
+		}
+		if !strings.Contains(string(hclBytes), expected) {
+			t.Errorf("%s does not contain '%s':\n%s", path, expected, hclBytes)
+		}
+	}
+}
+
+func TestExportDriftReport(t *testing.T) {
+	exporters := getResourceExporters([]string{"genesyscloud_routing_queue", "genesyscloud_routing_skill", "genesyscloud_user"})
+	provider := New("0.1.0")()
+	newResourceTypeJSONMaps := func() map[string]map[string]jsonMap {
+		return map[string]map[string]jsonMap{
+			"genesyscloud_routing_queue": {
+				"queue_1": jsonMap{
+					"name":        "Queue 1",
+					"description": nil,
+					"bullseye_rings": []interface{}{
+						map[string]interface{}{
+							"expansion_timeout_seconds": float64(10),
+							"skills_to_remove":          []interface{}{"${genesyscloud_routing_skill.skill_1.id}"},
+						},
+					},
+				},
+			},
+			"genesyscloud_routing_skill": {
+				"skill_1": jsonMap{"name": "Skill 1"},
+				"skill_2": jsonMap{"name": "Skill 2"},
+			},
+			"genesyscloud_user": {
+				"user_1": jsonMap{"name": "User 1", "email": "user1@example.com"},
+			},
+		}
+	}
+
+	// Export as HCL modules with variables so references must be resolved to compare resources
+	directory := t.TempDir()
+	exported := newResourceTypeJSONMaps()
+	variables := extractVariables(exported, exporters, provider)
+	if err := writeVariables(variables, directory, true, false, exporters, provider); err != nil {
+		t.Fatal(err)
+	}
+	rootPath := filepath.Join(directory, defaultTfHCLFile)
+	if err := writeConfigModulePerType(exported, rootPath, true, exporters, provider, "genesys.com/mypurecloud/genesyscloud", "0.1.0", variables, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	report, diagErr := writeDriftReport(newResourceTypeJSONMaps(), exporters, directory)
+	if diagErr != nil {
+		t.Fatalf("Failed to write drift report: %v", diagErr)
+	}
+	if report.hasDrift() {
+		t.Errorf("Expected no drift for an unchanged org. Found %+v", report)
+	}
+
+	current := newResourceTypeJSONMaps()
+	current["genesyscloud_routing_queue"]["queue_1"]["name"] = "Queue One"
+	current["genesyscloud_user"]["user_1"]["email"] = "user.one@example.com"
+	delete(current["genesyscloud_routing_skill"], "skill_2")
+	current["genesyscloud_routing_skill"]["skill_3"] = jsonMap{"name": "Skill 3"}
+
+	report, diagErr = writeDriftReport(current, exporters, directory)
+	if diagErr != nil {
+		t.Fatalf("Failed to write drift report: %v", diagErr)
+	}
+	expectedReport := &driftReport{
+		Directory: directory,
+		Added:     []driftResource{{Type: "genesyscloud_routing_skill", Name: "skill_3"}},
+		Removed:   []driftResource{{Type: "genesyscloud_routing_skill", Name: "skill_2"}},
+		Modified: []driftResource{
+			{
+				Type:       "genesyscloud_routing_queue",
+				Name:       "queue_1",
+				Attributes: []driftAttributeDiff{{Path: "name", Exported: "Queue 1", Current: "Queue One"}},
+			},
+			{
+				Type:       "genesyscloud_user",
+				Name:       "user_1",
+				Attributes: []driftAttributeDiff{{Path: "email", Exported: "user1@example.com", Current: "user.one@example.com"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(report, expectedReport) {
+		t.Errorf("Unexpected drift report:\n%+v\nExpected:\n%+v", report, expectedReport)
+	}
+
+	var jsonReport driftReport
+	readTestJSONFile(t, filepath.Join(directory, "drift_report.json"), &jsonReport)
+	if len(jsonReport.Modified) != 2 {
+		t.Errorf("Expected 2 modified resources in the JSON report. Found %d", len(jsonReport.Modified))
+	}
+	markdown, err := ioutil.ReadFile(filepath.Join(directory, "drift_report.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(markdown), "| `name` | `\"Queue 1\"` | `\"Queue One\"` |") {
+		t.Errorf("Unexpected markdown report:\n%s", markdown)
+	}
+}
+
+func TestExportDataSourceReferences(t *testing.T) {
+	exporters := getResourceExporters([]string{"genesyscloud_architect_ivr"})
+	exporters["genesyscloud_architect_ivr"].SanitizedResourceMap = ResourceIDMetaMap{"ivr-1": {Name: "ivr_1"}}
+	resources := []resourceInfo{{Type: "genesyscloud_architect_ivr", Name: "ivr_1"}}
+	configMaps := []jsonMap{{
+		"name":                  "IVR 1",
+		"open_hours_flow_id":    "flow-1",
+		"closed_hours_flow_id":  "flow-2",
+		"holiday_hours_flow_id": "flow-missing",
+	}}
+
+	flowNames := map[string]string{"flow-1": "MainFlow", "flow-2": "Closed ${Flow}"}
+	nameFuncs := map[string]GetResourceNameFunc{
+		"genesyscloud_flow": func(_ context.Context, id string, _ interface{}) (string, diag.Diagnostics) {
+			if name, ok := flowNames[id]; ok {
+				return name, nil
+			}
+			return "", diag.Errorf("Flow %s not found", id)
+		},
+	}
+
+	dataSources := buildDataSources(resources, configMaps, exporters, nameFuncs, nil)
+	sanitizeConfigMap("genesyscloud_architect_ivr", configMaps[0], "", exporters, dataSources, false)
+
+	closedFlowName := dataSources["genesyscloud_flow"]["flow-2"].Name
+	expectedConfig := jsonMap{
+		"name":                  "IVR 1",
+		"open_hours_flow_id":    "${data.genesyscloud_flow.MainFlow.id}",
+		"closed_hours_flow_id":  "${data.genesyscloud_flow." + closedFlowName + ".id}",
+		"holiday_hours_flow_id": nil,
+	}
+	if !reflect.DeepEqual(configMaps[0], expectedConfig) {
+		t.Errorf("Unexpected config %v. Expected %v", configMaps[0], expectedConfig)
+	}
+
+	dataSourceMaps := dataSources.jsonMaps()
+	if name := dataSourceMaps["genesyscloud_flow"][closedFlowName]["name"]; name != "Closed $${Flow}" {
+		t.Errorf("Expected the data source to look up the escaped flow name. Found %v", name)
+	}
+
+	path := filepath.Join(t.TempDir(), defaultTfHCLFile)
+	rootJSONObject := jsonMap{
+		"data":     dataSourceMaps,
+		"resource": map[string]map[string]jsonMap{"genesyscloud_architect_ivr": {"ivr_1": configMaps[0]}},
+	}
+	if err := writeHCLConfig(rootJSONObject, exporters, New("0.1.0")(), path); err != nil {
+		t.Fatal(err)
+	}
+	hclBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"data \"genesyscloud_flow\" \"MainFlow\" {\n  name = \"MainFlow\"\n}",
+		"open_hours_flow_id   = data.genesyscloud_flow.MainFlow.id",
+	} {
+		if !strings.Contains(string(hclBytes), expected) {
+			t.Errorf("Exported HCL does not contain '%s':\n%s", expected, hclBytes)
+		}
+	}
+}
+
+func TestExportResourceFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prompt-1.wav" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+
+	exportDir := t.TempDir()
+	exporters := map[string]*ResourceExporter{
+		"genesyscloud_architect_user_prompt": {
+			ExportFilesFunc: func(ctx context.Context, id string, resName string, configMap jsonMap, exportDir string, meta interface{}) diag.Diagnostics {
+				filename := filepath.Join(promptsExportDir, resName+"-en-us.wav")
+				if err := downloadExportFile(ctx, server.URL+"/"+id+".wav", filepath.Join(exportDir, filename)); err != nil {
+					return diag.FromErr(err)
+				}
+				configMap["filename"] = filepath.ToSlash(filename)
+				return nil
+			},
+		},
+	}
+	resources := []resourceInfo{{State: &terraform.InstanceState{ID: "prompt-1"}, Type: "genesyscloud_architect_user_prompt", Name: "prompt_1"}}
+	resourceTypeJSONMaps := map[string]map[string]jsonMap{
+		"genesyscloud_architect_user_prompt": {"prompt_1": {"filename": "greeting.wav"}},
+	}
+
+	if err := exportResourceFiles(resources, resourceTypeJSONMaps, exporters, exportDir, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if filename := resourceTypeJSONMaps["genesyscloud_architect_user_prompt"]["prompt_1"]["filename"]; filename != "prompts/prompt_1-en-us.wav" {
+		t.Errorf("Expected filename to reference the downloaded file. Found %v", filename)
+	}
+	audio, err := ioutil.ReadFile(filepath.Join(exportDir, promptsExportDir, "prompt_1-en-us.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(audio) != "audio" {
+		t.Errorf("Unexpected downloaded file content %s", audio)
+	}
+
+	resources[0].State.ID = "prompt-missing"
+	if err := exportResourceFiles(resources, resourceTypeJSONMaps, exporters, exportDir, nil, nil); err == nil {
+		t.Error("Expected an error downloading a missing file")
+	}
+
+	// Errors are recorded in the report when continuing on errors
+	report := newExportReport()
+	if err := exportResourceFiles(resources, resourceTypeJSONMaps, exporters, exportDir, nil, report); err != nil {
+		t.Errorf("Expected the file error to be recorded in the report. Found %v", err)
+	}
+	if _, ok := report.ResourceTypes["genesyscloud_architect_user_prompt"].FileErrors["prompt-missing"]; !ok || report.fileErrorCount() != 1 {
+		t.Errorf("Expected a file error for the missing prompt. Found %+v", report.ResourceTypes["genesyscloud_architect_user_prompt"])
+	}
+}
+
+func TestExportFileTimeout(t *testing.T) {
+	stalled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-stalled:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(stalled)
+
+	defaultTimeout := exportFileTimeout
+	exportFileTimeout = 100 * time.Millisecond
+	defer func() { exportFileTimeout = defaultTimeout }()
+
+	path := filepath.Join(t.TempDir(), "prompt-1.wav")
+	if err := downloadExportFile(context.Background(), server.URL+"/prompt-1.wav", path); err == nil {
+		t.Error("Expected a stalled download to time out")
+	}
+}
+
+func TestExportUserPromptFiles(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/architect/prompts/prompt-1":
+			w.Header().Set("Content-Type", "application/json")
+			// Audio uploaded in the UI has no filename tag, and prompt resources without audio have no media URI
+			w.Write([]byte(`{"id": "prompt-1", "name": "Prompt1", "resources": [
+				{"language": "en-us", "mediaUri": "` + server.URL + `/media/en-us.wav"},
+				{"language": "es-us", "mediaUri": "` + server.URL + `/media/missing.mp3", "tags": {"filename": ["hola.mp3"]}},
+				{"language": "fr-ca", "ttsString": "Bonjour"}
+			]}`))
+		case "/media/en-us.wav":
+			w.Write([]byte("audio"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Config read from the org, before the audio is exported
+	newConfig := func() jsonMap {
+		return jsonMap{
+			"name":        "Prompt1",
+			"description": "Greeting",
+			"resources": []interface{}{
+				map[string]interface{}{"language": "en-us"},
+				map[string]interface{}{"language": "es-us", "filename": "hola.mp3"},
+				map[string]interface{}{"language": "fr-ca", "tts_string": "Bonjour"},
+			},
+		}
+	}
+
+	directory := t.TempDir()
+	clientConfig := platformclientv2.NewConfiguration()
+	clientConfig.BasePath = server.URL
+	config := newConfig()
+	diagErr := exportUserPromptFiles(context.Background(), "prompt-1", "prompt_1", config, directory, clientConfig)
+	if diagErr == nil || !strings.Contains(diagnosticsString(diagErr), "es-us") {
+		t.Errorf("Expected an error downloading the es-us audio. Found %v", diagErr)
+	}
+	expectedResources := []interface{}{
+		map[string]interface{}{"language": "en-us", "filename": "prompts/prompt_1-en-us.wav"},
+		map[string]interface{}{"language": "es-us", "filename": "hola.mp3"},
+		map[string]interface{}{"language": "fr-ca", "tts_string": "Bonjour"},
+	}
+	if !reflect.DeepEqual(config["resources"], expectedResources) {
+		t.Errorf("Expected the audio without a filename tag to be exported. Found %v", config["resources"])
+	}
+	if audio, err := ioutil.ReadFile(filepath.Join(directory, promptsExportDir, "prompt_1-en-us.wav")); err != nil || string(audio) != "audio" {
+		t.Errorf("Expected the en-us audio to be downloaded. Found %s: %v", audio, err)
+	}
+
+	resType := "genesyscloud_architect_user_prompt"
+	exporters := map[string]*ResourceExporter{resType: architectUserPromptExporter()}
+	rootJSONObject := jsonMap{
+		"resource": map[string]map[string]jsonMap{resType: {"prompt_1": config}},
+	}
+	if err := writeConfig(rootJSONObject, filepath.Join(directory, defaultTfJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The paths of the downloaded files are not drift
+	report, diagErr := writeDriftReport(map[string]map[string]jsonMap{resType: {"prompt_1": newConfig()}}, exporters, directory)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if report.hasDrift() {
+		t.Errorf("Expected no drift for an exported prompt. Found %+v", report)
+	}
+
+	// Nor are they changes to the state read from the org
+	provider := New("0.1.0")()
+	promptResource := provider.ResourcesMap[resType]
+	d := promptResource.TestResourceData()
+	d.SetId("prompt-1")
+	d.Set("name", "Prompt1")
+	d.Set("description", "Greeting")
+	d.Set("resources", newConfig()["resources"])
+	resources := []resourceInfo{{
+		Type:    resType,
+		Name:    "prompt_1",
+		State:   d.State(),
+		CtyType: promptResource.CoreConfigSchema().ImpliedType(),
+	}}
+	verifyResult, diagErr := verifyExport(context.Background(), resources, nil, exportedDataSources{}, exporters, provider, nil, directory)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if verifyResult.Verified != 1 {
+		t.Errorf("Expected the exported prompt to be verified. Found %+v", verifyResult.Failed)
+	}
+}
+
+func TestExportStableNames(t *testing.T) {
+	directory := t.TempDir()
+
+	// Objects with the same name are suffixed based on their ID
+	idMetaMap := ResourceIDMetaMap{
+		"queue-1": {Name: "Sales"},
+		"queue-2": {Name: "Sales"},
+		"queue-3": {Name: "Support"},
+	}
+	applyResourceNames(idMetaMap, nil)
+	if idMetaMap["queue-1"].Name != "Sales" || idMetaMap["queue-2"].Name != "Sales_"+getIDNameSuffix("queue-2") {
+		t.Errorf("Unexpected names for duplicate objects: %s, %s", idMetaMap["queue-1"].Name, idMetaMap["queue-2"].Name)
+	}
+
+	exporters := map[string]*ResourceExporter{"genesyscloud_routing_queue": {SanitizedResourceMap: idMetaMap}}
+	manifest := exportNamesManifest{"genesyscloud_user": {"user-1": "user_1"}}
+	if err := writeNamesManifest(manifest, exporters, directory); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := readNamesManifest(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest["genesyscloud_user"]["user-1"] != "user_1" || manifest["genesyscloud_routing_queue"]["queue-3"] != "Support" {
+		t.Errorf("Unexpected names manifest %v", manifest)
+	}
+
+	// Renamed objects keep their previous name, and new objects do not take a previous name
+	idMetaMap = ResourceIDMetaMap{
+		"queue-1": {Name: "Sales"},
+		"queue-2": {Name: "Sales"},
+		"queue-3": {Name: "Customer_Support"},
+		"queue-4": {Name: "Support"},
+	}
+	applyResourceNames(idMetaMap, manifest["genesyscloud_routing_queue"])
+	expectedNames := map[string]string{
+		"queue-1": "Sales",
+		"queue-2": "Sales_" + getIDNameSuffix("queue-2"),
+		"queue-3": "Support",
+		"queue-4": "Support_" + getIDNameSuffix("queue-4"),
+	}
+	for id, expected := range expectedNames {
+		if idMetaMap[id].Name != expected {
+			t.Errorf("Expected %s to be named %s. Found %s", id, expected, idMetaMap[id].Name)
+		}
+	}
+
+	// Set elements are sorted, including sets in nested blocks
+	configMap := map[string]interface{}{
+		"wrapup_codes": []interface{}{"code-2", "code-3", "code-1"},
+		"bullseye_rings": []interface{}{
+			map[string]interface{}{"skills_to_remove": []interface{}{"skill-2", "skill-1"}},
+		},
+		"members": []interface{}{
+			map[string]interface{}{"user_id": "user-2", "ring_num": 1},
+			map[string]interface{}{"user_id": "user-1", "ring_num": 1},
+		},
+	}
+	sortSetElements(configMap, resourceRoutingQueue().Schema)
+	expectedConfig := map[string]interface{}{
+		"wrapup_codes": []interface{}{"code-1", "code-2", "code-3"},
+		"bullseye_rings": []interface{}{
+			map[string]interface{}{"skills_to_remove": []interface{}{"skill-1", "skill-2"}},
+		},
+		"members": []interface{}{
+			map[string]interface{}{"user_id": "user-1", "ring_num": 1},
+			map[string]interface{}{"user_id": "user-2", "ring_num": 1},
+		},
+	}
+	if !reflect.DeepEqual(configMap, expectedConfig) {
+		t.Errorf("Unexpected config %v. Expected %v", configMap, expectedConfig)
+	}
+}
+
+func TestExportNamesManifestFiltered(t *testing.T) {
+	directory := t.TempDir()
+	resType := "genesyscloud_routing_queue"
+	resources := ResourceIDMetaMap{
+		"queue-1": {Name: "Sales", DivisionID: "div-1"},
+		"queue-2": {Name: "Support", DivisionID: "div-1"},
+	}
+	export := func(divisionIDs []string) map[string]string {
+		manifest, diagErr := readNamesManifest(directory)
+		if diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		exporters := newTestExporters([]string{resType}, resources)
+		exporters[resType].ExportedNames = manifest[resType]
+		exporters[resType].DivisionIDs = divisionIDs
+		if diagErr := buildSanitizedResourceMaps(exporters, nil, nil); diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		if diagErr := writeNamesManifest(manifest, exporters, directory); diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		names := make(map[string]string)
+		for id, meta := range exporters[resType].SanitizedResourceMap {
+			names[id] = meta.Name
+		}
+		return names
+	}
+	export(nil)
+
+	// A filtered export does not give a new object the name of an object it filtered out
+	resources["queue-0"] = &ResourceMeta{Name: "Sales", DivisionID: "div-2"}
+	resources["queue-1"].Name = "Sales East"
+	names := export([]string{"div-2"})
+	if !reflect.DeepEqual(names, map[string]string{"queue-0": "Sales_" + getIDNameSuffix("queue-0")}) {
+		t.Errorf("Unexpected names in the filtered export %v", names)
+	}
+
+	// A later full export keeps the names of the objects filtered out before
+	names = export(nil)
+	expectedNames := map[string]string{
+		"queue-0": "Sales_" + getIDNameSuffix("queue-0"),
+		"queue-1": "Sales",
+		"queue-2": "Support",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("Unexpected names after a filtered export %v. Expected %v", names, expectedNames)
+	}
+
+	// Objects that no longer exist are removed from the manifest
+	delete(resources, "queue-2")
+	export([]string{"div-2"})
+	manifest, diagErr := readNamesManifest(directory)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	delete(expectedNames, "queue-2")
+	if !reflect.DeepEqual(manifest[resType], expectedNames) {
+		t.Errorf("Unexpected names manifest %v. Expected %v", manifest[resType], expectedNames)
+	}
+}
+
+func TestExportContinueOnError(t *testing.T) {
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"genesyscloud_test": {
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Optional: true},
+				},
+				ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					if d.Id() == "broken" {
+						return diag.Errorf("Failed to read asset")
+					}
+					d.Set("name", d.Id())
+					return nil
+				},
+			},
+		},
+	}
+	newExporters := func() map[string]*ResourceExporter {
+		return map[string]*ResourceExporter{
+			"genesyscloud_test": {
+				GetResourcesFunc: func(context.Context, interface{}) (ResourceIDMetaMap, diag.Diagnostics) {
+					return ResourceIDMetaMap{"ok": {Name: "ok"}, "broken": {Name: "broken"}}, nil
+				},
+			},
+			"genesyscloud_failed": {
+				GetResourcesFunc: func(context.Context, interface{}) (ResourceIDMetaMap, diag.Diagnostics) {
+					return nil, diag.Errorf("Failed to list resources")
+				},
+			},
+		}
+	}
+
+	// Errors fail the export by default
+	exporters := newExporters()
+	if err := buildSanitizedResourceMaps(exporters, nil, nil); err == nil {
+		t.Error("Expected an error loading resources")
+	}
+	exporters = newExporters()
+	delete(exporters, "genesyscloud_failed")
+	if err := buildSanitizedResourceMaps(exporters, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getResourcesForType("genesyscloud_test", provider, exporters["genesyscloud_test"], nil, nil); err == nil {
+		t.Error("Expected an error reading resources")
+	}
+
+	report := newExportReport()
+	exporters = newExporters()
+	if err := buildSanitizedResourceMaps(exporters, nil, report); err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]int{"genesyscloud_test": len(exporters["genesyscloud_test"].SanitizedResourceMap)}
+	resources, err := getResourcesForType("genesyscloud_test", provider, exporters["genesyscloud_test"], nil, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 || resources[0].State.ID != "ok" {
+		t.Errorf("Expected only the readable resource to be exported. Found %v", resources)
+	}
+	if exporters["genesyscloud_test"].SanitizedResourceMap["broken"] != nil {
+		t.Error("Expected the skipped resource to be removed from the resource map")
+	}
+
+	directory := t.TempDir()
+	if err := report.write(exporters, found, resources, directory); err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]map[string]exportTypeReport
+	readTestJSONFile(t, filepath.Join(directory, defaultExportReportFile), &written)
+	testReport := written["resource_types"]["genesyscloud_test"]
+	if testReport.Found != 2 || testReport.Exported != 1 || testReport.Skipped != 1 || !strings.Contains(testReport.Errors["broken"], "Failed to read asset") {
+		t.Errorf("Unexpected report for genesyscloud_test: %+v", testReport)
+	}
+	if failedReport := written["resource_types"]["genesyscloud_failed"]; !strings.Contains(failedReport.Error, "Failed to list resources") {
+		t.Errorf("Unexpected report for genesyscloud_failed: %+v", failedReport)
+	}
+}
+
+func TestExportIncremental(t *testing.T) {
+	directory := t.TempDir()
+	existingConfig := jsonMap{
+		"resource": map[string]interface{}{
+			"genesyscloud_routing_queue": map[string]interface{}{
+				"unmodified": map[string]interface{}{"name": "unmodified", "queue_flow_id": "${data.genesyscloud_flow.MainFlow.id}"},
+				"modified":   map[string]interface{}{"name": "modified"},
+				"deleted":    map[string]interface{}{"name": "deleted"},
+			},
+			"genesyscloud_group": map[string]interface{}{
+				"group_1": map[string]interface{}{"name": "group_1"},
+			},
+			"genesyscloud_user": map[string]interface{}{
+				"user_2": map[string]interface{}{"email": "user2@example.com"},
+				"user_3": map[string]interface{}{"email": "user3@example.com"},
+			},
+		},
+		"data": map[string]interface{}{
+			"genesyscloud_flow": map[string]interface{}{
+				"MainFlow": map[string]interface{}{"name": "MainFlow"},
+				"Unused":   map[string]interface{}{"name": "Unused"},
+			},
+		},
+	}
+	if err := writeConfig(existingConfig, filepath.Join(directory, defaultTfJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	modifiedSince, _ := time.Parse(time.RFC3339, "2021-08-01T00:00:00Z")
+	before := modifiedSince.Add(-time.Hour)
+	after := modifiedSince.Add(time.Hour)
+	version3, version5 := 3, 5
+	exporters := map[string]*ResourceExporter{
+		"genesyscloud_routing_queue": {
+			SanitizedResourceMap: ResourceIDMetaMap{
+				"queue-1": {Name: "unmodified", DateModified: &before},
+				"queue-2": {Name: "modified", DateModified: &after},
+				"queue-3": {Name: "new", DateModified: &before},
+			},
+		},
+		"genesyscloud_user": {
+			SanitizedResourceMap: ResourceIDMetaMap{
+				"user-1": {Name: "user_1"},
+				"user-2": {Name: "user_2", Version: &version3},
+				"user-3": {Name: "user_3", Version: &version5},
+			},
+		},
+	}
+	// Versions recorded by the previous export
+	previousVersions := exportVersionsManifest{"genesyscloud_user": {"user-2": 3, "user-3": 4}}
+
+	existingResources, existingDataSources, diagErr := loadExportedResources(directory, false)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	report := newExportReport()
+	unmodified := markUnmodifiedResources(exporters, existingResources, modifiedSince, previousVersions, report)
+	unmodifiedIDs := make(map[string]bool)
+	for _, resource := range unmodified {
+		unmodifiedIDs[resource.ImportID] = true
+	}
+	// Resources with the same version as the previous export are not read again
+	if !reflect.DeepEqual(unmodifiedIDs, map[string]bool{"queue-1": true, "user-2": true}) {
+		t.Errorf("Expected only queue-1 and user-2 to be unmodified. Found %v", unmodified)
+	}
+	// Resources without a modification date or version are always read
+	if count := report.ResourceTypes["genesyscloud_user"].NoModificationDate; count != 1 {
+		t.Errorf("Expected 1 user without a modification date in the report. Found %d", count)
+	}
+	if report.ResourceTypes["genesyscloud_routing_queue"] != nil {
+		t.Errorf("Expected all queues to have a modification date. Found %+v", report.ResourceTypes["genesyscloud_routing_queue"])
+	}
+	if !reflect.DeepEqual(exporters["genesyscloud_routing_queue"].UnmodifiedIDs, map[string]bool{"queue-1": true}) {
+		t.Errorf("Unexpected unmodified IDs %v", exporters["genesyscloud_routing_queue"].UnmodifiedIDs)
+	}
+
+	// Modified and new resources are read again
+	resourceTypeJSONMaps := map[string]map[string]jsonMap{
+		"genesyscloud_routing_queue": {
+			"modified": {"name": "modified", "description": "updated"},
+			"new":      {"name": "new"},
+		},
+		"genesyscloud_user": {
+			"user_1": {"email": "user1@example.com"},
+			"user_3": {"email": "user3@example.com", "title": "updated"},
+		},
+	}
+	dataSources := make(exportedDataSources)
+	if diagErr := mergeExportedResources(resourceTypeJSONMaps, unmodified, existingResources, existingDataSources, exporters, dataSources); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+
+	expected := map[string]map[string]jsonMap{
+		"genesyscloud_routing_queue": {
+			"unmodified": {"name": "unmodified", "queue_flow_id": "${data.genesyscloud_flow.MainFlow.id}"},
+			"modified":   {"name": "modified", "description": "updated"},
+			"new":        {"name": "new"},
+		},
+		"genesyscloud_group": {
+			"group_1": {"name": "group_1"},
+		},
+		"genesyscloud_user": {
+			"user_1": {"email": "user1@example.com"},
+			"user_2": {"email": "user2@example.com"},
+			"user_3": {"email": "user3@example.com", "title": "updated"},
+		},
+	}
+	if !reflect.DeepEqual(resourceTypeJSONMaps, expected) {
+		t.Errorf("Unexpected merged resources %v. Expected %v", resourceTypeJSONMaps, expected)
+	}
+	expectedDataSources := map[string]map[string]jsonMap{
+		"genesyscloud_flow": {"MainFlow": {"name": "MainFlow"}},
+	}
+	if dataSourceMaps := dataSources.jsonMaps(); !reflect.DeepEqual(dataSourceMaps, expectedDataSources) {
+		t.Errorf("Unexpected data sources %v. Expected %v", dataSourceMaps, expectedDataSources)
+	}
+
+	// The versions of the exported objects are recorded for the next export
+	exporters["genesyscloud_user"].ListedIDs = map[string]bool{"user-1": true, "user-2": true, "user-3": true}
+	if diagErr := writeVersionsManifest(previousVersions, exporters, directory); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	versions, diagErr := readVersionsManifest(directory)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if expectedVersions := (exportVersionsManifest{"genesyscloud_user": {"user-2": 3, "user-3": 5}}); !reflect.DeepEqual(versions, expectedVersions) {
+		t.Errorf("Unexpected versions manifest %v. Expected %v", versions, expectedVersions)
+	}
+}
+
+func TestExportReplaceAttributes(t *testing.T) {
+	exporters := getResourceExporters([]string{"genesyscloud_user"})
+	exporters["genesyscloud_user"].SanitizedResourceMap = ResourceIDMetaMap{"manager@example.com": {Name: "manager"}}
+	diagErr := populateConfigReplaced(exporters, []interface{}{
+		map[string]interface{}{"attribute": "genesyscloud_user.email", "pattern": "@example\\.com$", "replacement": "@sandbox.example.com"},
+		map[string]interface{}{"attribute": "genesyscloud_user.addresses", "pattern": "^\\+1317", "replacement": "+1555"},
+		map[string]interface{}{"attribute": "genesyscloud_user.title", "pattern": "(\\w+) Agent", "replacement": "$${$1}"},
+		map[string]interface{}{"attribute": "genesyscloud_user.manager", "pattern": "example", "replacement": "sandbox"},
+	})
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+
+	configMap := map[string]interface{}{
+		"email":   "john@example.com",
+		"title":   "Sales Agent",
+		"manager": "manager@example.com",
+		"addresses": []interface{}{
+			map[string]interface{}{
+				"phone_numbers": []interface{}{
+					map[string]interface{}{"number": "+13175550100"},
+				},
+			},
+		},
+	}
+	sanitizeConfigMap("genesyscloud_user", configMap, "", exporters, nil, false)
+
+	expectedConfig := map[string]interface{}{
+		"email":   "john@sandbox.example.com",
+		"title":   "$${Sales}",
+		"manager": "${genesyscloud_user.manager.id}",
+		"addresses": []interface{}{
+			map[string]interface{}{
+				"phone_numbers": []interface{}{
+					map[string]interface{}{"number": "+15555550100"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(configMap, expectedConfig) {
+		t.Errorf("Unexpected config %v. Expected %v", configMap, expectedConfig)
+	}
+
+	if diagErr := populateConfigReplaced(exporters, []interface{}{
+		map[string]interface{}{"attribute": "genesyscloud_group.name", "pattern": "a", "replacement": "b"},
+	}); diagErr == nil {
+		t.Error("Expected an error replacing an attribute of a type that is not exported")
+	}
+}
+
+func TestExportVerify(t *testing.T) {
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"genesyscloud_test": {
+				Schema: map[string]*schema.Schema{
+					"name":        {Type: schema.TypeString, Required: true},
+					"description": {Type: schema.TypeString, Optional: true},
+					"email":       {Type: schema.TypeString, Optional: true},
+					"enabled":     {Type: schema.TypeBool, Optional: true, Default: true},
+					"ref_id":      {Type: schema.TypeString, Optional: true},
+				},
+			},
+		},
+	}
+	resources := []resourceInfo{
+		{
+			Type: "genesyscloud_test",
+			Name: "disabled",
+			State: &terraform.InstanceState{ID: "id-1", Attributes: map[string]string{
+				"id": "id-1", "name": "disabled", "enabled": "false",
+			}},
+		},
+		{
+			Type: "genesyscloud_test",
+			Name: "referencing",
+			State: &terraform.InstanceState{ID: "id-2", Attributes: map[string]string{
+				"id": "id-2", "name": "referencing", "enabled": "true", "description": "${escaped}", "email": "john@example.com", "ref_id": "id-1",
+			}},
+		},
+	}
+
+	// Zero values are removed from the config, so the disabled resource would be enabled
+	directory := t.TempDir()
+	rootJSONObject := jsonMap{
+		"resource": map[string]map[string]jsonMap{
+			"genesyscloud_test": {
+				"disabled": {"name": "disabled"},
+				"referencing": {
+					"name":        "referencing",
+					"description": "$${escaped}",
+					"email":       "${var.test_referencing_email}",
+					"ref_id":      "${genesyscloud_test.disabled.id}",
+				},
+			},
+		},
+	}
+	if err := writeConfig(rootJSONObject, filepath.Join(directory, defaultTfJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+	variables := []*exportVariable{{Name: "test_referencing_email", Value: "john@example.com", Sensitive: true}}
+
+	report, diagErr := verifyExport(context.Background(), resources, variables, exportedDataSources{}, nil, provider, nil, directory)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if report.Verified != 1 || len(report.Failed) != 1 || report.Failed[0].Address != "genesyscloud_test.disabled" {
+		t.Fatalf("Expected only genesyscloud_test.disabled to fail verification. Found %+v", report)
+	}
+	expectedChanges := []verifyAttributeChange{{Attribute: "enabled", State: "false", Config: "true"}}
+	if !reflect.DeepEqual(report.Failed[0].Changes, expectedChanges) {
+		t.Errorf("Unexpected changes %+v. Expected %+v", report.Failed[0].Changes, expectedChanges)
+	}
+
+	var written verifyReport
+	readTestJSONFile(t, filepath.Join(directory, defaultVerifyReportFile), &written)
+	if written.Verified != 1 || len(written.Failed) != 1 {
+		t.Errorf("Unexpected verify report %+v", written)
+	}
+}
+
+func TestExportVerifyCustomizeDiff(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/telephony/providers/edges/phonebasesettings/pbs-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "pbs-1", "properties": {"phone_label": {"value": {"instance": "Phone"}}}}`))
+	}))
+	defer server.Close()
+
+	provider := New("0.1.0")()
+	pool := newSDKClientPool(1, &providerCredentials{AccessToken: "token"}, server.URL)
+	if diagErr := pool.preFill(schema.TestResourceDataRaw(t, provider.Schema, map[string]interface{}{"sdk_base_path": server.URL}), "0.1.0"); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	// The export runs with the provider's meta, so the plan must get a client from the pool
+	meta := &providerMeta{ClientPool: pool}
+
+	resType := "genesyscloud_telephony_providers_edges_phonebasesettings"
+	properties := `{"phone_label":{"value":{"instance":"Phone"}}}`
+	phoneResource := provider.ResourcesMap[resType]
+	d := phoneResource.TestResourceData()
+	d.SetId("pbs-1")
+	d.Set("name", "Phone")
+	d.Set("phone_meta_base_id", "meta-1")
+	d.Set("properties", properties)
+	d.Set("capabilities", []interface{}{})
+	resources := []resourceInfo{{
+		Type:    resType,
+		Name:    "pbs_1",
+		State:   d.State(),
+		CtyType: phoneResource.CoreConfigSchema().ImpliedType(),
+	}}
+
+	directory := t.TempDir()
+	rootJSONObject := jsonMap{
+		"resource": map[string]map[string]jsonMap{
+			resType: {"pbs_1": {"name": "Phone", "phone_meta_base_id": "meta-1", "properties": properties}},
+		},
+	}
+	if err := writeConfig(rootJSONObject, filepath.Join(directory, defaultTfJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	report, diagErr := verifyExport(context.Background(), resources, nil, exportedDataSources{}, nil, provider, meta, directory)
+	if diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if report.Verified != 1 || requests != 1 {
+		t.Errorf("Expected the phone base settings to be verified with their defaults. Found %d requests and %+v", requests, report)
+	}
+}
+
+// Returns the exporters of the resource types with each exporter listing a copy of the same resources
+func newTestExporters(resTypes []string, resources ResourceIDMetaMap) map[string]*ResourceExporter {
+	exporters := getResourceExporters(resTypes)
+	for _, exporter := range exporters {
+		exporter.GetResourcesFunc = func(context.Context, interface{}) (ResourceIDMetaMap, diag.Diagnostics) {
+			// Names are sanitized in place, so each call gets its own copy
+			result := make(ResourceIDMetaMap, len(resources))
+			for id, meta := range resources {
+				metaCopy := *meta
+				result[id] = &metaCopy
+			}
+			return result, nil
+		}
+	}
+	return exporters
+}
+
+func readTestJSONFile(t *testing.T, path string, v interface{}) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("Failed to parse %s: %v", path, err)
+	}
+}
+
 func isIgnoredReferenceCycle(cycle []string) bool {
 	// Some cycles cannot be broken with a schema change and must be dealt with in the config
 	// These cycles can be ignored by this test
@@ -230,4 +1606,4 @@ func validateConfigFile(path string) resource.TestCheckFunc {
 		}
 		return nil
 	}
-}
\ No newline at end of file
+}
@@ -125,7 +125,7 @@ func getAllUsers(_ context.Context, sdkConfig *platformclientv2.Configuration) (
 		}
 
 		for _, user := range *users.Entities {
-			resources[*user.Id] = &ResourceMeta{Name: *user.Email}
+			resources[*user.Id] = &ResourceMeta{Name: *user.Email, Version: user.Version, DivisionID: getDivisionID(user.Division)}
 		}
 	}
 
@@ -135,6 +135,7 @@ func getAllUsers(_ context.Context, sdkConfig *platformclientv2.Configuration) (
 func userExporter() *ResourceExporter {
 	return &ResourceExporter{
 		GetResourcesFunc: getAllWithPooledClient(getAllUsers),
+		DivisionAware:    true,
 		RefAttrs: map[string]*RefAttrSettings{
 			"manager":                       {RefType: "genesyscloud_user"},
 			"division_id":                   {RefType: "genesyscloud_auth_division"},
@@ -148,6 +149,9 @@ func userExporter() *ResourceExporter {
 			"locations":         {"location_id"},
 		},
 		AllowZeroValues: []string{"routing_skills.proficiency" ,"routing_languages.proficiency"},
+		VariableAttrs: map[string]*VariableAttrSettings{
+			"email": {},
+		},
 	}
 }
 
@@ -460,17 +464,17 @@ func createUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
-	diagErr := updateUserSkills(d, usersAPI)
+	diagErr := updateUserSkills(ctx, d, usersAPI)
 	if diagErr != nil {
 		return diagErr
 	}
 
-	diagErr = updateUserLanguages(d, usersAPI)
+	diagErr = updateUserLanguages(ctx, d, usersAPI)
 	if diagErr != nil {
 		return diagErr
 	}
 
-	diagErr = updateUserProfileSkills(d, usersAPI)
+	diagErr = updateUserProfileSkills(ctx, d, usersAPI)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -576,7 +580,7 @@ func updateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	// If state changes, it is the only modifiable field, so it must be updated separately
 	if d.HasChange("state") {
 		log.Printf("Updating state for user %s", email)
-		patchErr := patchUser(d.Id(), platformclientv2.Updateuser{
+		patchErr := patchUser(ctx, d.Id(), platformclientv2.Updateuser{
 			State: &state,
 		}, usersAPI)
 		if patchErr != nil {
@@ -584,7 +588,7 @@ func updateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
-	patchErr := patchUser(d.Id(), platformclientv2.Updateuser{
+	patchErr := patchUser(ctx, d.Id(), platformclientv2.Updateuser{
 		Name:           &name,
 		Email:          &email,
 		Department:     &department,
@@ -600,22 +604,22 @@ func updateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return patchErr
 	}
 
-	diagErr := updateObjectDivision(d, "USER", sdkConfig)
+	diagErr := updateObjectDivision(d, "USER", meta)
 	if diagErr != nil {
 		return diagErr
 	}
 
-	diagErr = updateUserSkills(d, usersAPI)
+	diagErr = updateUserSkills(ctx, d, usersAPI)
 	if diagErr != nil {
 		return diagErr
 	}
 
-	diagErr = updateUserLanguages(d, usersAPI)
+	diagErr = updateUserLanguages(ctx, d, usersAPI)
 	if diagErr != nil {
 		return diagErr
 	}
 
-	diagErr = updateUserProfileSkills(d, usersAPI)
+	diagErr = updateUserProfileSkills(ctx, d, usersAPI)
 	if diagErr != nil {
 		return diagErr
 	}
@@ -637,7 +641,7 @@ func deleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	usersAPI := platformclientv2.NewUsersApiWithConfig(sdkConfig)
 
 	log.Printf("Deleting user %s", email)
-	err := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+	err := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		// Directory occasionally returns version errors on deletes if an object was updated at the same time.
 		_, resp, err := usersAPI.DeleteUser(d.Id())
 		if err != nil {
@@ -663,12 +667,12 @@ func deleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	})
 }
 
-func patchUser(id string, update platformclientv2.Updateuser, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
-	return patchUserWithState(id, "", update, usersAPI)
+func patchUser(ctx context.Context, id string, update platformclientv2.Updateuser, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
+	return patchUserWithState(ctx, id, "", update, usersAPI)
 }
 
-func patchUserWithState(id string, state string, update platformclientv2.Updateuser, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
-	return retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+func patchUserWithState(ctx context.Context, id string, state string, update platformclientv2.Updateuser, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
+	return retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 		currentUser, _, getErr := usersAPI.GetUser(id, nil, "", state)
 		if getErr != nil {
 			return nil, diag.Errorf("Failed to read user %s: %s", id, getErr)
@@ -714,7 +718,7 @@ func restoreDeletedUser(ctx context.Context, d *schema.ResourceData, meta interf
 	state := d.Get("state").(string)
 
 	log.Printf("Restoring deleted user %s", email)
-	patchErr := patchUserWithState(d.Id(), "deleted", platformclientv2.Updateuser{
+	patchErr := patchUserWithState(ctx, d.Id(), "deleted", platformclientv2.Updateuser{
 		State: &state,
 	}, usersAPI)
 	if patchErr != nil {
@@ -965,7 +969,7 @@ func readUserRoutingUtilization(d *schema.ResourceData, usersAPI *platformclient
 	return nil
 }
 
-func updateUserSkills(d *schema.ResourceData, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
+func updateUserSkills(ctx context.Context, d *schema.ResourceData, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
 	if d.HasChange("routing_skills") {
 		if skillsConfig := d.Get("routing_skills"); skillsConfig != nil {
 			sdkSkills := make([]platformclientv2.Userroutingskillpost, 0)
@@ -982,7 +986,7 @@ func updateUserSkills(d *schema.ResourceData, usersAPI *platformclientv2.UsersAp
 				})
 			}
 
-			return retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+			return retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 				_, resp, err := usersAPI.PutUserRoutingskillsBulk(d.Id(), sdkSkills)
 				if err != nil {
 					return resp, diag.Errorf("Failed to update skills for user %s: %s", d.Id(), err)
@@ -994,7 +998,7 @@ func updateUserSkills(d *schema.ResourceData, usersAPI *platformclientv2.UsersAp
 	return nil
 }
 
-func updateUserLanguages(d *schema.ResourceData, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
+func updateUserLanguages(ctx context.Context, d *schema.ResourceData, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
 	if d.HasChange("routing_languages") {
 		if languages := d.Get("routing_languages"); languages != nil {
 			log.Printf("Updating languages for user %s", d.Get("email"))
@@ -1022,7 +1026,7 @@ func updateUserLanguages(d *schema.ResourceData, usersAPI *platformclientv2.User
 			if len(oldLangIds) > 0 {
 				langsToRemove := sliceDifference(oldLangIds, newLangIds)
 				for _, langID := range langsToRemove {
-					diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+					diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 						resp, err := usersAPI.DeleteUserRoutinglanguage(d.Id(), langID)
 						if err != nil {
 							return resp, diag.Errorf("Failed to remove language from user %s: %s", d.Id(), err)
@@ -1047,7 +1051,7 @@ func updateUserLanguages(d *schema.ResourceData, usersAPI *platformclientv2.User
 						}
 					}
 				}
-				if diagErr := updateUserRoutingLanguages(d.Id(), langsToAddOrUpdate, newLangProfs, usersAPI); diagErr != nil {
+				if diagErr := updateUserRoutingLanguages(ctx, d.Id(), langsToAddOrUpdate, newLangProfs, usersAPI); diagErr != nil {
 					return diagErr
 				}
 			}
@@ -1076,6 +1080,7 @@ func getUserRoutingLanguages(userID string, api *platformclientv2.UsersApi) ([]p
 }
 
 func updateUserRoutingLanguages(
+	ctx context.Context,
 	userID string,
 	langsToUpdate []string,
 	langProfs map[string]int,
@@ -1098,7 +1103,7 @@ func updateUserRoutingLanguages(
 		}
 
 		if len(updateChunk) > 0 {
-			diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+			diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 				_, resp, err := api.PatchUserRoutinglanguagesBulk(userID, updateChunk)
 				if err != nil {
 					return resp, diag.Errorf("Failed to update languages for user %s: %s", userID, err)
@@ -1113,11 +1118,11 @@ func updateUserRoutingLanguages(
 	return nil
 }
 
-func updateUserProfileSkills(d *schema.ResourceData, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
+func updateUserProfileSkills(ctx context.Context, d *schema.ResourceData, usersAPI *platformclientv2.UsersApi) diag.Diagnostics {
 	if d.HasChange("profile_skills") {
 		if profileSkills := d.Get("profile_skills"); profileSkills != nil {
 			profileSkills := setToStringList(profileSkills.(*schema.Set))
-			diagErr := retryWhen(isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+			diagErr := retryWhen(ctx, isVersionMismatch, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 				_, resp, err := usersAPI.PutUserProfileskills(d.Id(), *profileSkills)
 				if err != nil {
 					return resp, diag.Errorf("Failed to update profile skills for user %s: %s", d.Id(), err)
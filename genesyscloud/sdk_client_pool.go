@@ -2,8 +2,12 @@ package genesyscloud
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,42 +18,77 @@ import (
 // acquired at the beginning of any resource operation and released on completion.
 // This has the benefit of ensuring we don't issue too many concurrent requests and also
 // increases throughput as each token will have its own rate limit.
+// Clients are authorized when they are first acquired, and again when their token expires or a request is unauthorized.
 type SDKClientPool struct {
-	pool chan *platformclientv2.Configuration
+	pool        chan *platformclientv2.Configuration
+	credentials *providerCredentials
+
+	// Time the token of each authorized client expires. Clients with a token that does not expire are not included.
+	tokenExpiry      map[*platformclientv2.Configuration]time.Time
+	tokenExpiryMutex sync.Mutex
+
+	// Paces the clients acquired from the pool when their requests are retried
+	throttler *clientThrottler
+
+	// Retry policy of the provider, used to set a new backoff with jitter for each acquired client
+	retryPolicy *retryPolicy
 }
 
-var sdkClientPool *SDKClientPool
-var sdkClientPoolErr diag.Diagnostics
-var once sync.Once
+// Tokens are renewed when they are this close to expiring so they do not expire during a request
+const tokenExpiryMargin = time.Minute
 
 // InitSDKClientPool creates a new pool of Clients with the given provider config
 // This must be called during provider initialization before the pool is used
-func InitSDKClientPool(max int, version string, providerConfig *schema.ResourceData) diag.Diagnostics {
-	once.Do(func() {
-		log.Print("Initializing default SDK client.")
-		// Initialize the default config for tests and anything else that doesn't use the pool
-		err := initClientConfig(providerConfig, version, platformclientv2.GetDefaultConfiguration())
-		if err != nil {
-			sdkClientPoolErr = err
-			return
-		}
+func InitSDKClientPool(max int, version string, providerConfig *schema.ResourceData, credentials *providerCredentials) (*SDKClientPool, diag.Diagnostics) {
+	basePath, err := getProviderBasePath(providerConfig)
+	if err != nil {
+		return nil, err
+	}
 
-		log.Printf("Initializing %d SDK clients in the pool.", max)
-		sdkClientPool = &SDKClientPool{
-			pool: make(chan *platformclientv2.Configuration, max),
-		}
-		sdkClientPoolErr = sdkClientPool.preFill(providerConfig, version)
-	})
-	return sdkClientPoolErr
+	log.Printf("Initializing %d SDK clients in the pool.", max)
+	clientPool := newSDKClientPool(max, credentials, basePath)
+	if err := clientPool.preFill(providerConfig, version); err != nil {
+		return nil, err
+	}
+	return clientPool, nil
+}
+
+func newSDKClientPool(max int, credentials *providerCredentials, name string) *SDKClientPool {
+	return &SDKClientPool{
+		pool:        make(chan *platformclientv2.Configuration, max),
+		credentials: credentials,
+		tokenExpiry: make(map[*platformclientv2.Configuration]time.Time),
+		throttler:   newClientThrottler(name),
+	}
+}
+
+// Logs the request counters of the pool when the provider is stopped. The plugin SDK sets the stop context of the
+// provider on the context passed to configure.
+func (p *SDKClientPool) logStatsOnStop(ctx context.Context) {
+	stopCtx, ok := ctx.Value(schema.StopContextKey).(context.Context)
+	if !ok {
+		return
+	}
+	go func() {
+		<-stopCtx.Done()
+		p.throttler.logStats()
+	}()
 }
 
 func (p *SDKClientPool) preFill(providerConfig *schema.ResourceData, version string) diag.Diagnostics {
+	retryPolicy, err := getRetryPolicy(providerConfig)
+	if err != nil {
+		return err
+	}
+	p.retryPolicy = retryPolicy
+
 	for cap(p.pool) > 0 {
 		sdkConfig := platformclientv2.NewConfiguration()
 		err := initClientConfig(providerConfig, version, sdkConfig)
 		if err != nil {
 			return err
 		}
+		p.throttler.attach(sdkConfig)
 
 		select {
 		case p.pool <- sdkConfig:
@@ -61,11 +100,27 @@ func (p *SDKClientPool) preFill(providerConfig *schema.ResourceData, version str
 	return nil
 }
 
-func (p *SDKClientPool) acquire() *platformclientv2.Configuration {
-	return <-p.pool
+// Acquires a client from the pool, authorizing it if it has no token or its token has expired.
+// Waits for the throttler if the requests of the clients are being retried, or the token of the client is running out
+// of its rate limit.
+func (p *SDKClientPool) acquire() (*platformclientv2.Configuration, diag.Diagnostics) {
+	p.throttler.wait()
+	c := <-p.pool
+	if c.AccessToken == "" || p.isTokenExpired(c) {
+		if err := p.authorize(c); err != nil {
+			p.release(c)
+			return nil, err
+		}
+	}
+	if p.retryPolicy != nil && c.RetryConfiguration != nil {
+		c.RetryConfiguration = p.retryPolicy.sdkRetryConfiguration(c.RetryConfiguration.RequestLogHook)
+	}
+	p.throttler.waitForToken(c.AccessToken)
+	return c, nil
 }
 
 func (p *SDKClientPool) release(c *platformclientv2.Configuration) {
+	p.throttler.done(c)
 	select {
 	case p.pool <- c:
 	default:
@@ -73,11 +128,87 @@ func (p *SDKClientPool) release(c *platformclientv2.Configuration) {
 	}
 }
 
+// Sets a new token for a client with the provider's credentials
+func (p *SDKClientPool) authorize(c *platformclientv2.Configuration) diag.Diagnostics {
+	if c.AccessToken != "" {
+		p.throttler.forgetToken(c.AccessToken)
+	}
+	expiry, err := p.credentials.authorize(c)
+	if err != nil {
+		return err
+	}
+
+	p.tokenExpiryMutex.Lock()
+	defer p.tokenExpiryMutex.Unlock()
+	if expiry.IsZero() {
+		delete(p.tokenExpiry, c)
+	} else {
+		p.tokenExpiry[c] = expiry
+	}
+	return nil
+}
+
+func (p *SDKClientPool) isTokenExpired(c *platformclientv2.Configuration) bool {
+	p.tokenExpiryMutex.Lock()
+	defer p.tokenExpiryMutex.Unlock()
+
+	expiry, ok := p.tokenExpiry[c]
+	return ok && time.Now().Add(tokenExpiryMargin).After(expiry)
+}
+
+// Authorizes a client again if a request failed as unauthorized. The token may have been revoked or expired
+// before its local expiry, so this does not depend on the expiry. A provided access token cannot be renewed.
+// Returns true if the client has a new token and the request should be retried.
+func (p *SDKClientPool) reauthorizeUnauthorized(c *platformclientv2.Configuration, diagErr diag.Diagnostics) bool {
+	if !isUnauthorizedError(diagErr) || p.credentials.AccessToken != "" {
+		return false
+	}
+	log.Print("Request was unauthorized. Authorizing the client again and retrying.")
+	if err := p.authorize(c); err != nil {
+		log.Printf("Failed to authorize the client again: %s", diagnosticsString(err))
+		return false
+	}
+	return true
+}
+
+// Returns true if a diagnostic contains an API error with a 401 status from the SDK
+func isUnauthorizedError(diagErr diag.Diagnostics) bool {
+	for _, d := range diagErr {
+		if d.Severity == diag.Error && (strings.Contains(d.Summary, "API Error: 401 ") || strings.Contains(d.Detail, "API Error: 401 ")) {
+			return true
+		}
+	}
+	return false
+}
+
+type responseObserverContextKey struct{}
+
+// Returns a context that reports the responses of a pooled client to the throttler, so its token is paced by the
+// rate limit headers of the responses
+func (p *SDKClientPool) withResponseObserver(ctx context.Context, c *platformclientv2.Configuration) context.Context {
+	return context.WithValue(ctx, responseObserverContextKey{}, func(resp *platformclientv2.APIResponse) {
+		p.throttler.observeResponse(c.AccessToken, http.Header(resp.Header))
+	})
+}
+
+// Reports a response to the pool of the client that made the request. The SDK does not expose its responses to the
+// pool, so methods run with a pooled client should call this with the responses they get, as retryWhen does.
+func observeResponse(ctx context.Context, resp *platformclientv2.APIResponse) {
+	if resp == nil {
+		return
+	}
+	if observe, ok := ctx.Value(responseObserverContextKey{}).(func(*platformclientv2.APIResponse)); ok {
+		observe(resp)
+	}
+}
+
 type resContextFunc func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
 type getAllConfigFunc func(context.Context, *platformclientv2.Configuration) (ResourceIDMetaMap, diag.Diagnostics)
+type getNameConfigFunc func(context.Context, string, *platformclientv2.Configuration) (string, diag.Diagnostics)
+type exportFilesConfigFunc func(context.Context, string, string, jsonMap, string, *platformclientv2.Configuration) diag.Diagnostics
 
 func createWithPooledClient(method resContextFunc) schema.CreateContextFunc {
-	return schema.CreateContextFunc(runWithPooledClient(method))
+	return schema.CreateContextFunc(refuseWhenReadOnly("create", runWithPooledClient(method)))
 }
 
 func readWithPooledClient(method resContextFunc) schema.ReadContextFunc {
@@ -85,19 +216,67 @@ func readWithPooledClient(method resContextFunc) schema.ReadContextFunc {
 }
 
 func updateWithPooledClient(method resContextFunc) schema.UpdateContextFunc {
-	return schema.UpdateContextFunc(runWithPooledClient(method))
+	return schema.UpdateContextFunc(refuseWhenReadOnly("update", runWithPooledClient(method)))
 }
 
 func deleteWithPooledClient(method resContextFunc) schema.DeleteContextFunc {
-	return schema.DeleteContextFunc(runWithPooledClient(method))
+	return schema.DeleteContextFunc(refuseWhenReadOnly("delete", runWithPooledClient(method)))
+}
+
+// Inject a pooled SDK client connection into a resource's CustomizeDiff method, which runs during plans
+func customizeDiffWithPooledClient(method schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		clientPool := meta.(*providerMeta).ClientPool
+		clientConfig, diagErr := clientPool.acquire()
+		if diagErr != nil {
+			return fmt.Errorf("%s", diagnosticsString(diagErr))
+		}
+		defer clientPool.release(clientConfig)
+		ctx = withRetryPolicy(ctx, meta.(*providerMeta).RetryPolicy)
+		ctx = clientPool.withResponseObserver(ctx, clientConfig)
+
+		// Copy to a new providerMeta object and set the sdk config
+		newMeta := *meta.(*providerMeta)
+		newMeta.ClientConfig = clientConfig
+
+		err := method(ctx, diff, &newMeta)
+		if err != nil && clientPool.reauthorizeUnauthorized(clientConfig, diag.FromErr(err)) {
+			err = method(ctx, diff, &newMeta)
+		}
+		return err
+	}
+}
+
+// Returns an error instead of running a method that changes the org if the provider is read-only
+func refuseWhenReadOnly(action string, method resContextFunc) resContextFunc {
+	return func(ctx context.Context, r *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if meta.(*providerMeta).ReadOnly {
+			detail := fmt.Sprintf("Unable to %s the resource because the provider is configured with read_only = true.", action)
+			if r.Id() != "" {
+				detail = fmt.Sprintf("Unable to %s resource %s because the provider is configured with read_only = true.", action, r.Id())
+			}
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "The provider is read-only",
+				Detail:   detail + " Remove read_only from the provider config, or unset GENESYSCLOUD_READ_ONLY, to apply changes.",
+			}}
+		}
+		return method(ctx, r, meta)
+	}
 }
 
 // Inject a pooled SDK client connection into a resource method's meta argument
 // and automatically return it to the pool on completion
 func runWithPooledClient(method resContextFunc) resContextFunc {
 	return func(ctx context.Context, r *schema.ResourceData, meta interface{}) diag.Diagnostics {
-		clientConfig := sdkClientPool.acquire()
-		defer sdkClientPool.release(clientConfig)
+		clientPool := meta.(*providerMeta).ClientPool
+		clientConfig, diagErr := clientPool.acquire()
+		if diagErr != nil {
+			return diagErr
+		}
+		defer clientPool.release(clientConfig)
+		ctx = withRetryPolicy(ctx, meta.(*providerMeta).RetryPolicy)
+		ctx = clientPool.withResponseObserver(ctx, clientConfig)
 
 		// Check if the request has been cancelled
 		select {
@@ -109,15 +288,28 @@ func runWithPooledClient(method resContextFunc) resContextFunc {
 		// Copy to a new providerMeta object and set the sdk config
 		newMeta := *meta.(*providerMeta)
 		newMeta.ClientConfig = clientConfig
-		return method(ctx, r, &newMeta)
+
+		// Only retry if the failed call did not create the resource, so it is not created twice
+		id := r.Id()
+		diagErr = method(ctx, r, &newMeta)
+		if r.Id() == id && clientPool.reauthorizeUnauthorized(clientConfig, diagErr) {
+			diagErr = method(ctx, r, &newMeta)
+		}
+		return diagErr
 	}
 }
 
 // Inject a pooled SDK client connection into an exporter's getAll* method
 func getAllWithPooledClient(method getAllConfigFunc) GetAllResourcesFunc {
-	return func(ctx context.Context) (ResourceIDMetaMap, diag.Diagnostics) {
-		clientConfig := sdkClientPool.acquire()
-		defer sdkClientPool.release(clientConfig)
+	return func(ctx context.Context, meta interface{}) (ResourceIDMetaMap, diag.Diagnostics) {
+		clientPool := meta.(*providerMeta).ClientPool
+		clientConfig, diagErr := clientPool.acquire()
+		if diagErr != nil {
+			return nil, diagErr
+		}
+		defer clientPool.release(clientConfig)
+		ctx = withRetryPolicy(ctx, meta.(*providerMeta).RetryPolicy)
+		ctx = clientPool.withResponseObserver(ctx, clientConfig)
 
 		// Check if the request has been cancelled
 		select {
@@ -126,6 +318,64 @@ func getAllWithPooledClient(method getAllConfigFunc) GetAllResourcesFunc {
 		default:
 		}
 
-		return method(ctx, clientConfig)
+		resources, diagErr := method(ctx, clientConfig)
+		if clientPool.reauthorizeUnauthorized(clientConfig, diagErr) {
+			resources, diagErr = method(ctx, clientConfig)
+		}
+		return resources, diagErr
+	}
+}
+
+// Inject a pooled SDK client connection into a data source's getName method
+func getNameWithPooledClient(method getNameConfigFunc) GetResourceNameFunc {
+	return func(ctx context.Context, id string, meta interface{}) (string, diag.Diagnostics) {
+		clientPool := meta.(*providerMeta).ClientPool
+		clientConfig, diagErr := clientPool.acquire()
+		if diagErr != nil {
+			return "", diagErr
+		}
+		defer clientPool.release(clientConfig)
+		ctx = withRetryPolicy(ctx, meta.(*providerMeta).RetryPolicy)
+		ctx = clientPool.withResponseObserver(ctx, clientConfig)
+
+		// Check if the request has been cancelled
+		select {
+		case <-ctx.Done():
+			return "", diag.FromErr(ctx.Err()) // Error somewhere, terminate
+		default:
+		}
+
+		name, diagErr := method(ctx, id, clientConfig)
+		if clientPool.reauthorizeUnauthorized(clientConfig, diagErr) {
+			name, diagErr = method(ctx, id, clientConfig)
+		}
+		return name, diagErr
+	}
+}
+
+// Inject a pooled SDK client connection into an exporter's file export method
+func exportFilesWithPooledClient(method exportFilesConfigFunc) ExportFilesFunc {
+	return func(ctx context.Context, id string, resName string, configMap jsonMap, exportDir string, meta interface{}) diag.Diagnostics {
+		clientPool := meta.(*providerMeta).ClientPool
+		clientConfig, diagErr := clientPool.acquire()
+		if diagErr != nil {
+			return diagErr
+		}
+		defer clientPool.release(clientConfig)
+		ctx = withRetryPolicy(ctx, meta.(*providerMeta).RetryPolicy)
+		ctx = clientPool.withResponseObserver(ctx, clientConfig)
+
+		// Check if the request has been cancelled
+		select {
+		case <-ctx.Done():
+			return diag.FromErr(ctx.Err()) // Error somewhere, terminate
+		default:
+		}
+
+		diagErr = method(ctx, id, resName, configMap, exportDir, clientConfig)
+		if clientPool.reauthorizeUnauthorized(clientConfig, diagErr) {
+			diagErr = method(ctx, id, resName, configMap, exportDir, clientConfig)
+		}
+		return diagErr
 	}
 }
@@ -0,0 +1,342 @@
+package genesyscloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
+)
+
+func TestSDKClientPoolReauthorization(t *testing.T) {
+	var (
+		mutex        sync.Mutex
+		tokensIssued int
+		validToken   string
+		rejectAll    bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/token":
+			tokensIssued++
+			validToken = fmt.Sprintf("token-%d", tokensIssued)
+			w.Write([]byte(`{"access_token": "` + validToken + `", "expires_in": 86400}`))
+		case "/api/v2/authorization/divisions/home":
+			if rejectAll || r.Header.Get("Authorization") != "Bearer "+validToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"status": 401, "code": "bad.credentials", "message": "Invalid login credentials."}`))
+				return
+			}
+			w.Write([]byte(`{"id": "home"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Expires the token of the current client and makes the server reject it
+	expireToken := func(pool *SDKClientPool, c *platformclientv2.Configuration) {
+		pool.tokenExpiryMutex.Lock()
+		pool.tokenExpiry[c] = time.Now().Add(-time.Minute)
+		pool.tokenExpiryMutex.Unlock()
+		mutex.Lock()
+		validToken = ""
+		mutex.Unlock()
+	}
+	getTokensIssued := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return tokensIssued
+	}
+	getHomeDivision := func(c *platformclientv2.Configuration) diag.Diagnostics {
+		_, _, err := platformclientv2.NewAuthorizationApiWithConfig(c).GetAuthorizationDivisionsHome()
+		if err != nil {
+			return diag.Errorf("Failed to query home division: %s", err)
+		}
+		return nil
+	}
+
+	providerData := schema.TestResourceDataRaw(t, New("0.1.0")().Schema, map[string]interface{}{
+		"sdk_base_path": server.URL,
+	})
+	pool := newSDKClientPool(2, &providerCredentials{ClientID: "id", ClientSecret: "secret", AuthBasePath: server.URL}, server.URL)
+	if diagErr := pool.preFill(providerData, "0.1.0"); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if issued := getTokensIssued(); issued != 0 {
+		t.Errorf("Expected clients to be authorized when first acquired. Found %d tokens issued", issued)
+	}
+	meta := &providerMeta{ClientPool: pool}
+
+	// A call that fails because its token expired is retried once with a new token
+	calls := 0
+	getAll := getAllWithPooledClient(func(_ context.Context, c *platformclientv2.Configuration) (ResourceIDMetaMap, diag.Diagnostics) {
+		calls++
+		if calls == 1 {
+			expireToken(pool, c)
+		}
+		if diagErr := getHomeDivision(c); diagErr != nil {
+			return nil, diagErr
+		}
+		return ResourceIDMetaMap{"home": {Name: "Home"}}, nil
+	})
+	if _, diagErr := getAll(context.Background(), meta); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if issued := getTokensIssued(); calls != 2 || issued != 2 {
+		t.Errorf("Expected one retry with a new token. Found %d calls and %d tokens issued", calls, issued)
+	}
+
+	// Tokens rejected before their local expiry, e.g. revoked tokens, are also renewed
+	calls = 0
+	read := readWithPooledClient(func(_ context.Context, _ *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		calls++
+		if calls == 1 {
+			mutex.Lock()
+			validToken = ""
+			mutex.Unlock()
+		}
+		return getHomeDivision(meta.(*providerMeta).ClientConfig)
+	})
+	resourceData := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+	if diagErr := read(context.Background(), resourceData, meta); diagErr != nil || calls != 2 {
+		t.Errorf("Expected one retry with a new token. Found %d calls: %v", calls, diagErr)
+	}
+
+	// Requests are only retried once
+	calls = 0
+	mutex.Lock()
+	rejectAll = true
+	mutex.Unlock()
+	if diagErr := read(context.Background(), resourceData, meta); diagErr == nil || calls != 2 {
+		t.Errorf("Expected the unauthorized error to be returned after one retry. Found %d calls", calls)
+	}
+	mutex.Lock()
+	rejectAll = false
+	mutex.Unlock()
+
+	// Calls that created the resource before failing are not retried
+	calls = 0
+	create := createWithPooledClient(func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		calls++
+		d.SetId("created")
+		expireToken(pool, meta.(*providerMeta).ClientConfig)
+		return getHomeDivision(meta.(*providerMeta).ClientConfig)
+	})
+	if diagErr := create(context.Background(), resourceData, meta); diagErr == nil || calls != 1 {
+		t.Errorf("Expected the create not to be retried. Found %d calls", calls)
+	}
+}
+
+func TestSDKClientThrottler(t *testing.T) {
+	var (
+		mutex     sync.Mutex
+		requests  int
+		rateLimit [2]string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if rateLimit[0] != "" {
+			w.Header().Set("inin-ratelimit-allowed", rateLimit[0])
+			w.Header().Set("inin-ratelimit-count", rateLimit[1])
+			w.Header().Set("inin-ratelimit-reset", "1")
+		}
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"status": 429, "code": "too.many.requests", "message": "Rate limit exceeded."}`))
+			return
+		}
+		w.Write([]byte(`{"id": "home"}`))
+	}))
+	defer server.Close()
+
+	providerData := schema.TestResourceDataRaw(t, New("0.1.0")().Schema, map[string]interface{}{
+		"sdk_base_path": server.URL,
+		"retry":         []interface{}{map[string]interface{}{"min_backoff_ms": 1, "max_backoff_ms": 10}},
+	})
+	pool := newSDKClientPool(1, &providerCredentials{AccessToken: "token"}, server.URL)
+	if diagErr := pool.preFill(providerData, "0.1.0"); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	getHomeDivision := func() {
+		c, diagErr := pool.acquire()
+		if diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		defer pool.release(c)
+		if _, _, err := platformclientv2.NewAuthorizationApiWithConfig(c).GetAuthorizationDivisionsHome(); err != nil {
+			t.Fatalf("Expected the rate limited request to be retried: %v", err)
+		}
+	}
+	getInterval := func() time.Duration {
+		pool.throttler.mutex.Lock()
+		defer pool.throttler.mutex.Unlock()
+		return pool.throttler.interval
+	}
+
+	// A retried request slows down acquiring clients
+	getHomeDivision()
+	stats := pool.throttler.getStats()
+	if stats.Requests != 2 || stats.Retried != 1 || stats.Delayed != 0 {
+		t.Errorf("Expected 2 requests with 1 retried. Found %+v", stats)
+	}
+	if interval := getInterval(); interval != throttleMinInterval {
+		t.Errorf("Expected clients to be paced after a retry. Found interval %v", interval)
+	}
+
+	// The next client is delayed, and operations without retries speed up again
+	getHomeDivision()
+	if paced := pool.throttler.getStats(); paced.Delayed != 1 || paced.Retried != 1 {
+		t.Errorf("Expected the next client to be delayed. Found %+v", paced)
+	}
+	if interval := getInterval(); interval != 0 {
+		t.Errorf("Expected clients not to be paced without retries. Found interval %v", interval)
+	}
+
+	// Responses observed by a pooled method pace the next operation of their token from its remaining budget
+	setRateLimit := func(allowed, count string) {
+		mutex.Lock()
+		rateLimit = [2]string{allowed, count}
+		mutex.Unlock()
+	}
+	getAll := getAllWithPooledClient(func(ctx context.Context, c *platformclientv2.Configuration) (ResourceIDMetaMap, diag.Diagnostics) {
+		return nil, retryWhen(ctx, isStatus404, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+			_, resp, err := platformclientv2.NewAuthorizationApiWithConfig(c).GetAuthorizationDivisionsHome()
+			if err != nil {
+				return resp, diag.FromErr(err)
+			}
+			return resp, nil
+		})
+	})
+	getDelay := func() time.Duration {
+		before := pool.throttler.getStats().TotalDelay
+		setRateLimit("", "")
+		if _, diagErr := getAll(context.Background(), &providerMeta{ClientPool: pool}); diagErr != nil {
+			t.Fatal(diagErr)
+		}
+		return pool.throttler.getStats().TotalDelay - before
+	}
+
+	// A token with most of its budget left is not paced
+	setRateLimit("100", "10")
+	if _, diagErr := getAll(context.Background(), &providerMeta{ClientPool: pool}); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if delay := getDelay(); delay != 0 {
+		t.Errorf("Expected no delay with most of the budget left. Found %v", delay)
+	}
+
+	// A token with little budget left spreads its remaining requests until the window resets
+	setRateLimit("100", "96")
+	if _, diagErr := getAll(context.Background(), &providerMeta{ClientPool: pool}); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if delay := getDelay(); delay <= 0 || delay > 300*time.Millisecond {
+		t.Errorf("Expected a delay of a quarter of the window with 4 requests left. Found %v", delay)
+	}
+
+	// A token that used its budget waits for the window to reset
+	setRateLimit("100", "100")
+	if _, diagErr := getAll(context.Background(), &providerMeta{ClientPool: pool}); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	if delay := getDelay(); delay < 500*time.Millisecond || delay > time.Second {
+		t.Errorf("Expected a delay until the window resets. Found %v", delay)
+	}
+}
+
+func TestSDKClientPoolReadOnly(t *testing.T) {
+	providerData := schema.TestResourceDataRaw(t, New("0.1.0")().Schema, map[string]interface{}{
+		"sdk_base_path": "https://api.example.com",
+	})
+	pool := newSDKClientPool(1, &providerCredentials{AccessToken: "token"}, "https://api.example.com")
+	if diagErr := pool.preFill(providerData, "0.1.0"); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	meta := &providerMeta{ClientPool: pool, ReadOnly: true}
+
+	calls := 0
+	method := func(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+		calls++
+		return nil
+	}
+	resourceData := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+	resourceData.SetId("id")
+
+	for action, run := range map[string]resContextFunc{
+		"create": resContextFunc(createWithPooledClient(method)),
+		"update": resContextFunc(updateWithPooledClient(method)),
+		"delete": resContextFunc(deleteWithPooledClient(method)),
+	} {
+		if diagErr := run(context.Background(), resourceData, meta); diagErr == nil {
+			t.Errorf("Expected %s to be refused by a read-only provider", action)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("Expected no changes by a read-only provider. Found %d calls", calls)
+	}
+
+	// Reads are not affected
+	if diagErr := readWithPooledClient(method)(context.Background(), resourceData, meta); diagErr != nil || calls != 1 {
+		t.Errorf("Expected reads to run with a read-only provider. Found %d calls: %v", calls, diagErr)
+	}
+}
+
+func TestSDKClientPoolCustomizeDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/telephony/providers/edges/phonebasesettings/pbs-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "pbs-1", "properties": {"phone_label": {"value": {"instance": "Phone"}}}}`))
+	}))
+	defer server.Close()
+
+	providerData := schema.TestResourceDataRaw(t, New("0.1.0")().Schema, map[string]interface{}{
+		"sdk_base_path": server.URL,
+	})
+	pool := newSDKClientPool(1, &providerCredentials{AccessToken: "token"}, server.URL)
+	if diagErr := pool.preFill(providerData, "0.1.0"); diagErr != nil {
+		t.Fatal(diagErr)
+	}
+	// Plans run CustomizeDiff with the provider's meta, which has no client of its own
+	meta := &providerMeta{ClientPool: pool}
+
+	resource := resourcePhoneBaseSettings()
+	resourceData := resource.TestResourceData()
+	resourceData.SetId("pbs-1")
+	resourceData.Set("name", "Phone")
+	resourceData.Set("phone_meta_base_id", "meta-1")
+	resourceData.Set("properties", "{}")
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "Phone",
+		"phone_meta_base_id": "meta-1",
+		"properties":         "{}",
+	})
+
+	diff, err := resource.SimpleDiff(context.Background(), resourceData.State(), config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Attributes["properties"] == nil || !strings.Contains(diff.Attributes["properties"].New, "phone_label") {
+		t.Errorf("Expected the properties to be set to the defaults of the phone base settings. Found %v", diff)
+	}
+}
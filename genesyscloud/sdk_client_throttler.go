@@ -0,0 +1,237 @@
+package genesyscloud
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
+)
+
+// Min and max time between acquiring clients once requests are being retried
+const (
+	throttleMinInterval = 100 * time.Millisecond
+	throttleMaxInterval = 5 * time.Second
+)
+
+// Max time to wait for the rate limit of a token to reset, in case of an unexpected header value
+const rateLimitMaxWait = time.Minute
+
+// Clients are paced once a token has used this share of the requests it is allowed, e.g. 4 paces the last quarter
+const rateLimitPacedShare = 4
+
+// clientThrottler paces how often a provider's clients are acquired from the pool. The retries the SDK makes for
+// rate limited responses and server errors are the signal that the org is busy. Each operation with retried requests
+// doubles the time between acquiring clients, and each operation without them halves it, so all clients slow down
+// before the org rejects their requests.
+// The SDK does not expose the responses it retries, but the responses returned to the pooled methods are observed
+// with observeResponse. Their rate limit headers pace each client token from its remaining budget.
+type clientThrottler struct {
+	mutex sync.Mutex
+
+	// Name of the org in the logs, e.g. the API base path
+	name string
+
+	// Min time between acquiring clients. Zero until requests are retried.
+	interval    time.Duration
+	lastAcquire time.Time
+
+	// Number of retried requests of each client during its current operation
+	retries map[*platformclientv2.Configuration]int
+
+	// Rate limit of each client token from the headers of its last response
+	limits map[string]*tokenRateLimit
+
+	stats throttlerStats
+}
+
+type throttlerStats struct {
+	Requests   int
+	Retried    int
+	Delayed    int
+	TotalDelay time.Duration
+}
+
+// tokenRateLimit is the rate limit of a token in its current window, from the inin-ratelimit-* headers
+type tokenRateLimit struct {
+	allowed int
+	count   int
+	reset   time.Time
+
+	// Time to wait until before the next request, from a Retry-After header
+	retryAfter time.Time
+}
+
+func newClientThrottler(name string) *clientThrottler {
+	return &clientThrottler{
+		name:    name,
+		retries: make(map[*platformclientv2.Configuration]int),
+		limits:  make(map[string]*tokenRateLimit),
+	}
+}
+
+// Counts the requests and retries of a client with the SDK's request hook
+func (t *clientThrottler) attach(config *platformclientv2.Configuration) {
+	if config.RetryConfiguration == nil {
+		config.RetryConfiguration = &platformclientv2.RetryConfiguration{}
+	}
+	requestLogHook := config.RetryConfiguration.RequestLogHook
+	config.RetryConfiguration.RequestLogHook = func(request *http.Request, count int) {
+		t.observe(config, count)
+		if requestLogHook != nil {
+			requestLogHook(request, count)
+		}
+	}
+}
+
+// Records a request of a client. The count is the number of the retry, or zero for the first attempt.
+func (t *clientThrottler) observe(config *platformclientv2.Configuration, count int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.Requests++
+	if count > 0 {
+		t.stats.Retried++
+		t.retries[config]++
+	}
+}
+
+// Records the rate limit headers of a response to a request made with a token. Responses without them are ignored.
+func (t *clientThrottler) observeResponse(token string, header http.Header) {
+	now := time.Now()
+	limit := &tokenRateLimit{}
+	found := false
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		limit.retryAfter = now.Add(time.Duration(seconds) * time.Second)
+		found = true
+	}
+	allowed, allowedErr := strconv.Atoi(header.Get("inin-ratelimit-allowed"))
+	count, countErr := strconv.Atoi(header.Get("inin-ratelimit-count"))
+	reset, resetErr := strconv.Atoi(header.Get("inin-ratelimit-reset"))
+	if allowedErr == nil && countErr == nil && resetErr == nil && allowed > 0 {
+		limit.allowed = allowed
+		limit.count = count
+		limit.reset = now.Add(time.Duration(reset) * time.Second)
+		found = true
+	}
+	if !found {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.limits[token] = limit
+}
+
+// Forgets the rate limit of a token that was replaced
+func (t *clientThrottler) forgetToken(token string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.limits, token)
+}
+
+// Waits until the rate limit of a token allows another operation. A token that has used its budget waits for its
+// window to reset, and a token that has used most of it spreads its remaining requests over the rest of the window.
+func (t *clientThrottler) waitForToken(token string) {
+	t.mutex.Lock()
+	delay := time.Duration(0)
+	exhausted := false
+	if limit, ok := t.limits[token]; ok {
+		now := time.Now()
+		remaining := limit.allowed - limit.count
+		switch {
+		case limit.retryAfter.After(now):
+			delay = limit.retryAfter.Sub(now)
+			exhausted = true
+			delete(t.limits, token)
+		case !limit.reset.After(now):
+			delete(t.limits, token)
+		case remaining <= 0:
+			delay = limit.reset.Sub(now)
+			exhausted = true
+			delete(t.limits, token)
+		case remaining*rateLimitPacedShare <= limit.allowed:
+			delay = limit.reset.Sub(now) / time.Duration(remaining)
+			// Reserve a request of the budget until the next response updates it
+			limit.count++
+		}
+	}
+	if delay > rateLimitMaxWait {
+		delay = rateLimitMaxWait
+	}
+	if exhausted {
+		log.Printf("A token has used its rate limit for requests to %s. Waiting %v.", t.name, delay.Round(time.Millisecond))
+	}
+	if delay > 0 {
+		t.stats.Delayed++
+		t.stats.TotalDelay += delay
+	}
+	t.mutex.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// Waits until another client may be acquired
+func (t *clientThrottler) wait() {
+	t.mutex.Lock()
+	now := time.Now()
+	delay := time.Duration(0)
+	if next := t.lastAcquire.Add(t.interval); t.interval > 0 && next.After(now) {
+		delay = next.Sub(now)
+	}
+	// Reserve the time of this client so concurrent acquires are paced after it
+	t.lastAcquire = now.Add(delay)
+	if delay > 0 {
+		t.stats.Delayed++
+		t.stats.TotalDelay += delay
+	}
+	t.mutex.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// Adjusts the pace of acquiring clients when a client is released at the end of its operation
+func (t *clientThrottler) done(config *platformclientv2.Configuration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	retries := t.retries[config]
+	delete(t.retries, config)
+	if retries > 0 {
+		if t.interval == 0 {
+			log.Printf("Requests to %s are being retried. Slowing down requests.", t.name)
+		}
+		t.interval *= 2
+		if t.interval < throttleMinInterval {
+			t.interval = throttleMinInterval
+		} else if t.interval > throttleMaxInterval {
+			t.interval = throttleMaxInterval
+		}
+		return
+	}
+	if t.interval > 0 {
+		t.interval /= 2
+		if t.interval < throttleMinInterval {
+			t.interval = 0
+			log.Printf("Requests to %s are no longer being retried. %d retried so far.", t.name, t.stats.Retried)
+		}
+	}
+}
+
+func (t *clientThrottler) getStats() throttlerStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.stats
+}
+
+func (t *clientThrottler) logStats() {
+	stats := t.getStats()
+	log.Printf("SDK client requests to %s: %d sent, %d retried after rate limits or server errors, %d clients delayed by the throttler for %v in total.",
+		t.name, stats.Requests, stats.Retried, stats.Delayed, stats.TotalDelay.Round(time.Millisecond))
+}
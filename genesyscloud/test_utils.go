@@ -3,11 +3,15 @@ package genesyscloud
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
 )
 
 const (
@@ -18,10 +22,46 @@ const (
 	testCert2  = "MIIDnjCCAoYCCQD9X0RdADwPozANBgkqhkiG9w0BAQsFADCBkDELMAkGA1UEBhMCVVMxCzAJBgNVBAgMAkNBMRIwEAYDVQQHDAlEYWx5IENpdHkxEDAOBgNVBAoMB0dlbmVzeXMxEDAOBgNVBAsMB1Byb2R1Y3QxGDAWBgNVBAMMD215cHVyZWNsb3VkLmNvbTEiMCAGCSqGSIb3DQEJARYTbm9yZXBseUBnZW5lc3lzLmNvbTAeFw0yMTAzMzAxMzM5NDJaFw0yMjAzMzAxMzM5NDJaMIGQMQswCQYDVQQGEwJVUzELMAkGA1UECAwCQ0ExEjAQBgNVBAcMCURhbHkgQ2l0eTEQMA4GA1UECgwHR2VuZXN5czEQMA4GA1UECwwHUHJvZHVjdDEYMBYGA1UEAwwPbXlwdXJlY2xvdWQuY29tMSIwIAYJKoZIhvcNAQkBFhNub3JlcGx5QGdlbmVzeXMuY29tMIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA6q37OAiuVFCNDejcxv3W3D9iDFUiZc/AtvRzfApH+QPLWyYfCgH5p7n5rOiezs3eY6Do6rvSk/Y9D0LZtafBQ/0TdYTakyc5+Q5rEJoP40DByJht3D9dK7ww8Z6avWYUvbRfNZCHtuykbcUC7RxTZuDKZlf2XV2DzzXYUTqojBKS5HuLkLREU2UhR47a1FEwErqQbNLD7FLsr2AYiP3EtlZDjwluGnRied/eOhVQuVSQ69rSewj2vK1QzMAUGyyaYKbK4xU7AA/gTAiYwGqFj0CPCC1g8NllfB6BDxmYrKD8ypTToJZbTWtOKFH1Wjw72Yi8NM5shXCg3wrsU1842wIDAQABMA0GCSqGSIb3DQEBCwUAA4IBAQC53LaV+RX4cgNUKJxLXybTxiXpY4RTDjX1Y2SPzY6hiqP4sNTiwKiPNCGtF4ySQpCh8QUonPS+a2g3zMZuq5JOtuQhDrebRSEyhy0YnUBPBMmzlBOBpgfXEgK8279bUznRg0MKwFb+67yWqXfoGYQJ3Sep4s94Y7bUJ04/+/P+fK0NUC03Oj5bejKzS9B+PWjJr47+IWzEVijAC8dsax7UUK7RNxGgc/dagWCWo4GNlIuBz946AD32Rx+XoGtIscI/OUsaNld7uLTSD2tygksedsBhrQ/0Sukom1mEAcPyEoYyeGs4izBZh0JdPJBXQ9ZDuj6Z7gNQFizyGK+oZP7p"
 )
 
+// Tests use the default SDK config, which is authorized by a client pool with the credentials in the environment
+var testClientPool *SDKClientPool
+var testClientPoolMutex sync.Mutex
+var testHomeDivision homeDivisionCache
+
+// Authorizes the default SDK config with the credentials in the environment, the same way a provider authorizes
+// the clients of its pool. The token is only renewed if it has expired.
+func authorizeTestDefaultConfig() diag.Diagnostics {
+	testClientPoolMutex.Lock()
+	defer testClientPoolMutex.Unlock()
+
+	if testClientPool == nil {
+		basePath := getRegionBasePath(os.Getenv("GENESYSCLOUD_REGION"))
+		testClientPool = newSDKClientPool(1, &providerCredentials{
+			ClientID:     os.Getenv("GENESYSCLOUD_OAUTHCLIENT_ID"),
+			ClientSecret: os.Getenv("GENESYSCLOUD_OAUTHCLIENT_SECRET"),
+		}, basePath)
+
+		defaultConfig := platformclientv2.GetDefaultConfiguration()
+		defaultConfig.BasePath = basePath
+		testClientPool.release(defaultConfig)
+	}
+
+	clientConfig, err := testClientPool.acquire()
+	if err != nil {
+		return err
+	}
+	testClientPool.release(clientConfig)
+	return nil
+}
+
+// Returns the home division of the org used by tests
+func getTestHomeDivisionID() (string, diag.Diagnostics) {
+	return testHomeDivision.get(platformclientv2.GetDefaultConfiguration())
+}
+
 // Verify default division is home division
 func testDefaultHomeDivision(resource string) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		homeDivID, err := getHomeDivisionID()
+		homeDivID, err := getTestHomeDivisionID()
 		if err != nil {
 			return fmt.Errorf("Failed to query home division: %v", err)
 		}
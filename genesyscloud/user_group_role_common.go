@@ -77,7 +77,7 @@ func readSubjectRoles(subjectID string, authAPI *platformclientv2.AuthorizationA
 	return roleSet, nil
 }
 
-func updateSubjectRoles(ctx context.Context, d *schema.ResourceData, authAPI *platformclientv2.AuthorizationApi, subjectType string) diag.Diagnostics {
+func updateSubjectRoles(ctx context.Context, d *schema.ResourceData, authAPI *platformclientv2.AuthorizationApi, subjectType string, meta interface{}) diag.Diagnostics {
 	if d.HasChange("roles") {
 		rolesConfig := d.Get("roles")
 		if rolesConfig != nil {
@@ -92,7 +92,7 @@ func updateSubjectRoles(ctx context.Context, d *schema.ResourceData, authAPI *pl
 				existingGrants = append(existingGrants, createRoleDivisionPair(*grant.Role.Id, *grant.Division.Id))
 			}
 
-			homeDiv, diagErr := getHomeDivisionID()
+			homeDiv, diagErr := getHomeDivisionID(meta)
 			if diagErr != nil {
 				return diagErr
 			}
@@ -137,7 +137,7 @@ func updateSubjectRoles(ctx context.Context, d *schema.ResourceData, authAPI *pl
 			grantsToAdd := sliceDifference(configGrants, existingGrants)
 			if len(grantsToAdd) > 0 {
 				// In some cases new roles or divisions have not yet been added to the auth service cache causing 404s that should be retried.
-				diagErr = retryWhen(isStatus404, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
+				diagErr = retryWhen(ctx, isStatus404, func() (*platformclientv2.APIResponse, diag.Diagnostics) {
 					resp, err := authAPI.PostAuthorizationSubjectBulkadd(d.Id(), roleDivPairsToGrants(grantsToAdd), subjectType)
 					if err != nil {
 						return resp, diag.Errorf("Failed to add role grants for subject %s: %s", d.Id(), err)
@@ -200,7 +200,7 @@ func validateResourceRole(resourceName string, roleResourceName string, division
 
 		if len(divisions) == 0 {
 			// If no division specified, role should be in the home division
-			homeDiv, err := getHomeDivisionID()
+			homeDiv, err := getTestHomeDivisionID()
 			if err != nil {
 				return fmt.Errorf("Failed to query home div: %v", err)
 			}
@@ -11,35 +11,43 @@ import (
 
 type jsonMap map[string]interface{}
 
-// Attempt to get the home division once during a provider run
-var divOnce sync.Once
-var homeDivID string
-var homeDivErr diag.Diagnostics
-
-func getHomeDivisionID() (string, diag.Diagnostics) {
-	divOnce.Do(func() {
-		authAPI := platformclientv2.NewAuthorizationApi()
+// homeDivisionCache holds the home division of a provider's org, which is queried once during a provider run
+type homeDivisionCache struct {
+	once sync.Once
+	id   string
+	err  diag.Diagnostics
+}
+
+func (c *homeDivisionCache) get(sdkConfig *platformclientv2.Configuration) (string, diag.Diagnostics) {
+	c.once.Do(func() {
+		authAPI := platformclientv2.NewAuthorizationApiWithConfig(sdkConfig)
 		homeDiv, _, err := authAPI.GetAuthorizationDivisionsHome()
 		if err != nil {
-			homeDivErr = diag.Errorf("Failed to query home division: %s", err)
+			c.err = diag.Errorf("Failed to query home division: %s", err)
 			return
 		}
-		homeDivID = *homeDiv.Id
+		c.id = *homeDiv.Id
 	})
 
-	if homeDivErr != nil {
-		return "", homeDivErr
+	if c.err != nil {
+		return "", c.err
 	}
-	return homeDivID, nil
+	return c.id, nil
+}
+
+// Returns the home division of the org the provider is configured for
+func getHomeDivisionID(meta interface{}) (string, diag.Diagnostics) {
+	providerMeta := meta.(*providerMeta)
+	return providerMeta.HomeDivision.get(providerMeta.ClientConfig)
 }
 
-func updateObjectDivision(d *schema.ResourceData, objType string, sdkConfig *platformclientv2.Configuration) diag.Diagnostics {
+func updateObjectDivision(d *schema.ResourceData, objType string, meta interface{}) diag.Diagnostics {
 	if d.HasChange("division_id") {
-		authAPI := platformclientv2.NewAuthorizationApiWithConfig(sdkConfig)
+		authAPI := platformclientv2.NewAuthorizationApiWithConfig(meta.(*providerMeta).ClientConfig)
 		divisionID := d.Get("division_id").(string)
 		if divisionID == "" {
 			// Default to home division
-			homeDivision, diagErr := getHomeDivisionID()
+			homeDivision, diagErr := getHomeDivisionID(meta)
 			if diagErr != nil {
 				return diagErr
 			}
@@ -53,3 +61,11 @@ func updateObjectDivision(d *schema.ResourceData, objType string, sdkConfig *pla
 	}
 	return nil
 }
+
+// Returns the ID of a division returned by the API, or an empty string if it is not set
+func getDivisionID(division *platformclientv2.Division) string {
+	if division == nil || division.Id == nil {
+		return ""
+	}
+	return *division.Id
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -12,11 +13,108 @@ import (
 	"github.com/mypurecloud/platform-client-sdk-go/v56/platformclientv2"
 )
 
+// retryPolicy is the retry and timeout policy of a provider, set with its retry block
+type retryPolicy struct {
+	// Max attempts of a request, including the first
+	MaxAttempts int
+
+	// Max retries of a request by the SDK after it is rate limited or fails with a server error
+	SDKMaxRetries int
+
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Max random time added to each wait
+	Jitter time.Duration
+
+	// Overrides the timeout of each resource for changes to become visible when set
+	EventualConsistencyTimeout time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	MaxAttempts:   10,
+	SDKMaxRetries: 20,
+	MinBackoff:    time.Second,
+	MaxBackoff:    30 * time.Second,
+}
+
+type retryPolicyContextKey struct{}
+
+// Returns the retry policy of the provider config, or the default policy if the retry block is not set
+func getRetryPolicy(data *schema.ResourceData) (*retryPolicy, diag.Diagnostics) {
+	policy := defaultRetryPolicy
+	retryBlocks := data.Get("retry").([]interface{})
+	if len(retryBlocks) == 0 || retryBlocks[0] == nil {
+		return &policy, nil
+	}
+
+	retryBlock := retryBlocks[0].(map[string]interface{})
+	if maxAttempts := retryBlock["max_attempts"].(int); maxAttempts > 0 {
+		policy.MaxAttempts = maxAttempts
+		policy.SDKMaxRetries = maxAttempts - 1
+	}
+	policy.MinBackoff = time.Duration(retryBlock["min_backoff_ms"].(int)) * time.Millisecond
+	policy.MaxBackoff = time.Duration(retryBlock["max_backoff_ms"].(int)) * time.Millisecond
+	policy.Jitter = time.Duration(retryBlock["jitter_ms"].(int)) * time.Millisecond
+	policy.EventualConsistencyTimeout = time.Duration(retryBlock["eventual_consistency_timeout_seconds"].(int)) * time.Second
+	if policy.MinBackoff > policy.MaxBackoff {
+		return nil, diag.Errorf("retry.min_backoff_ms (%d) must not be greater than retry.max_backoff_ms (%d)", policy.MinBackoff.Milliseconds(), policy.MaxBackoff.Milliseconds())
+	}
+	return &policy, nil
+}
+
+// Returns a context that carries the retry policy to withRetries and retryWhen
+func withRetryPolicy(ctx context.Context, policy *retryPolicy) context.Context {
+	if policy == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, retryPolicyContextKey{}, policy)
+}
+
+// Returns the retry policy of the provider running the request, or the default policy
+func getContextRetryPolicy(ctx context.Context) *retryPolicy {
+	if policy, ok := ctx.Value(retryPolicyContextKey{}).(*retryPolicy); ok {
+		return policy
+	}
+	return &defaultRetryPolicy
+}
+
+// Returns a random time up to the max jitter
+func (p *retryPolicy) jitter() time.Duration {
+	if p.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(p.Jitter) + 1))
+}
+
+// Returns the retry configuration of an SDK client. The SDK does not support jitter, so it is added to the min and
+// max backoff the SDK doubles for each retry. Clients get a new configuration each time they are acquired, so
+// clients whose requests failed together do not retry together.
+func (p *retryPolicy) sdkRetryConfiguration(requestLogHook platformclientv2.RequestLogHook) *platformclientv2.RetryConfiguration {
+	jitter := p.jitter()
+	return &platformclientv2.RetryConfiguration{
+		RetryWaitMin:   p.MinBackoff + jitter,
+		RetryWaitMax:   p.MaxBackoff + jitter,
+		RetryMax:       p.SDKMaxRetries,
+		RequestLogHook: requestLogHook,
+	}
+}
+
+// Returns the timeout to wait for changes to become visible, or the default timeout of the resource if it is not overridden
+func (p *retryPolicy) consistencyTimeout(timeout time.Duration) time.Duration {
+	if p.EventualConsistencyTimeout > 0 {
+		return p.EventualConsistencyTimeout
+	}
+	return timeout
+}
+
 func withRetries(ctx context.Context, timeout time.Duration, method func() *resource.RetryError) diag.Diagnostics {
+	timeout = getContextRetryPolicy(ctx).consistencyTimeout(timeout)
 	return diag.FromErr(resource.RetryContext(ctx, timeout, method))
 }
 
 func withRetriesForRead(ctx context.Context, timeout time.Duration, d *schema.ResourceData, method func() *resource.RetryError) diag.Diagnostics {
+	timeout = getContextRetryPolicy(ctx).consistencyTimeout(timeout)
 	err := diag.FromErr(resource.RetryContext(ctx, timeout, method))
 	if err != nil && strings.Contains(fmt.Sprintf("%v", err), "API Error: 404") {
 		// Set ID empty if the object isn't found after the specified timeout
@@ -28,17 +126,26 @@ func withRetriesForRead(ctx context.Context, timeout time.Duration, d *schema.Re
 type checkResponseFunc func(resp *platformclientv2.APIResponse) bool
 type callSdkFunc func() (*platformclientv2.APIResponse, diag.Diagnostics)
 
-// Retries up to 10 times while the shouldRetry condition returns true
-// Useful for adding custom retry logic to normally non-retryable error codes
-func retryWhen(shouldRetry checkResponseFunc, callSdk callSdkFunc) diag.Diagnostics {
+// Retries up to the max attempts of the retry policy while the shouldRetry condition returns true, waiting the min
+// backoff between attempts. Useful for adding custom retry logic to normally non-retryable error codes
+func retryWhen(ctx context.Context, shouldRetry checkResponseFunc, callSdk callSdkFunc) diag.Diagnostics {
+	policy := getContextRetryPolicy(ctx)
 	var lastErr diag.Diagnostics
-	for i := 0; i < 10; i++ {
+	for i := 0; i < policy.MaxAttempts; i++ {
+		if i > 0 {
+			timer := time.NewTimer(policy.MinBackoff + policy.jitter())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return diag.Errorf("Stopped retrying: %v. Last error: %v", ctx.Err(), lastErr)
+			case <-timer.C:
+			}
+		}
 		resp, sdkErr := callSdk()
+		observeResponse(ctx, resp)
 		if sdkErr != nil {
 			if resp != nil && shouldRetry(resp) {
-				// Wait a second and try again
 				lastErr = sdkErr
-				time.Sleep(time.Second)
 				continue
 			} else {
 				return sdkErr
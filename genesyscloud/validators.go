@@ -42,3 +42,32 @@ func validateLocalDateTimes(date interface{}, _ cty.Path) diag.Diagnostics {
 	}
 	return diag.Errorf("Date %v is not a string", date)
 }
+
+// Validates an export filter is a resource type or a resource type::regular expression
+func validateExportFilter(filter interface{}, _ cty.Path) diag.Diagnostics {
+	if filterStr, ok := filter.(string); ok {
+		resType, _, err := parseResourceFilter(filterStr)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !stringInSlice(resType, getAvailableExporterTypes()) {
+			return diag.Errorf("Resource type %s in filter %s cannot be exported", resType, filterStr)
+		}
+		return nil
+	}
+	return diag.Errorf("Filter %v is not a string", filter)
+}
+
+func validateRootResource(root interface{}, _ cty.Path) diag.Diagnostics {
+	if rootStr, ok := root.(string); ok {
+		refs, err := parseRootResources([]string{rootStr})
+		if err != nil {
+			return err
+		}
+		if !stringInSlice(refs[0].Type, getAvailableExporterTypes()) {
+			return diag.Errorf("Resource type %s in root resource %s cannot be exported", refs[0].Type, rootStr)
+		}
+		return nil
+	}
+	return diag.Errorf("Root resource %v is not a string", root)
+}
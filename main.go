@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	provider "github.com/mypurecloud/terraform-provider-genesyscloud/genesyscloud"
@@ -30,6 +31,15 @@ var (
 )
 
 func main() {
+	// Run an export without Terraform, e.g. terraform-provider-genesyscloud export --dir ./genesyscloud
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		err := provider.RunExportCommand(version, os.Args[2:], os.Stderr)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debugMode bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")